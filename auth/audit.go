@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"yard-calculation/logging"
+	"yard-calculation/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuditRecorder menyimpan catatan audit, contoh: services.AuditService
+type AuditRecorder interface {
+	RecordAudit(ctx context.Context, entry *models.AuditEntry) error
+}
+
+// Audit mencatat identitas pengirim, route, status, yard dan nomor kontainer setiap request yang mengubah data,
+// termasuk yang ditolak atau gagal. Dipasang setelah Middleware supaya identitas sudah diketahui; tanpa autentikasi
+// identitas dicatat sebagai anonymous. Gagal mencatat audit tidak menggagalkan request. readOnlyRoutes adalah pola
+// route yang memakai POST tanpa mengubah data (contoh: /suggestion), tidak dicatat.
+func Audit(recorder AuditRecorder, readOnlyRoutes ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return c.Next()
+		}
+
+		err := c.Next()
+		// Route baru pasti diketahui setelah c.Next jika Audit dipasang lewat app.Use
+		if slices.Contains(readOnlyRoutes, c.Route().Path) {
+			return err
+		}
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			if e, ok := err.(*fiber.Error); ok {
+				status = e.Code
+			}
+		}
+
+		ctx := c.UserContext()
+		user := "anonymous"
+		if p := FromContext(ctx); p != nil {
+			user = p.String()
+		}
+		yard, containerNumber := requestTarget(c)
+		entry := &models.AuditEntry{
+			User:            user,
+			Method:          c.Method(),
+			Route:           c.Route().Path,
+			Path:            strings.Clone(c.Path()),
+			Status:          status,
+			Yard:            yard,
+			ContainerNumber: containerNumber,
+			RequestID:       logging.RequestID(ctx),
+		}
+		// Audit tetap dicatat walaupun batas waktu request sudah habis
+		if recordErr := recorder.RecordAudit(context.WithoutCancel(ctx), entry); recordErr != nil {
+			slog.ErrorContext(ctx, "error recording audit entry", "error", recordErr)
+		}
+		return err
+	}
+}
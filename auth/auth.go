@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// Jenis identitas yang mengakses API
+const (
+	KindAPIKey = "api_key" // Client mesin, contoh: gate system
+	KindUser   = "user"    // Pengguna dengan JWT bearer token
+)
+
+// apiKeyPrefix menandai kunci milik aplikasi ini supaya mudah dikenali jika bocor (contoh: di log atau repository)
+const apiKeyPrefix = "yk_"
+
+// displayPrefixLength adalah panjang awal kunci yang disimpan untuk mengenali kunci di daftar
+const displayPrefixLength = len(apiKeyPrefix) + 6
+
+// ErrInvalidAPIKey dikembalikan untuk kunci yang tidak dikenal atau sudah dicabut, keduanya tidak dibedakan supaya
+// client tidak bisa menebak kunci mana yang pernah ada
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// Principal adalah identitas yang sudah terautentikasi untuk satu request
+type Principal struct {
+	Kind  string
+	Name  string   // Nama API key atau subject JWT
+	Roles []string // Menentukan permission, lihat rolePermissions
+	Yards []string // Yard tempat identitas ditugaskan, AllYards untuk semua yard
+}
+
+// String mengembalikan identitas untuk log dan audit, contoh: api_key:gate-yrd1 atau user:budi
+func (p *Principal) String() string {
+	return p.Kind + ":" + p.Name
+}
+
+type contextKey struct{}
+
+// WithPrincipal menyimpan identitas di context request
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext mengembalikan identitas dari context, nil jika request tidak terautentikasi
+func FromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(contextKey{}).(*Principal)
+	return p
+}
+
+// GenerateAPIKey membuat kunci acak baru. Kunci asli hanya diberikan ke pembuat, yang disimpan adalah hash dan
+// prefix-nya.
+func GenerateAPIKey() (key, prefix, hash string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+	key = apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return key, key[:displayPrefixLength], HashAPIKey(key), nil
+}
+
+// HashAPIKey menghitung hash kunci untuk disimpan dan dicari. Kunci berisi 256 bit acak, sehingga SHA-256 cukup
+// dan pencarian per request tetap cepat (berbeda dengan password yang perlu bcrypt).
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(key)))
+	return hex.EncodeToString(sum[:])
+}
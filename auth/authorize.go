@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"yard-calculation/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Require menolak request dengan 403 jika identitas tidak punya semua permission untuk route ini. Untuk request yang
+// mengubah data, yard yang disebut request (parameter path, query atau field yard di body JSON) juga harus termasuk
+// yard identitas, sehingga petugas YRD1 tidak bisa pickup kontainer di YRD2. Membaca data tidak dibatasi per yard.
+// Tanpa identitas (autentikasi nonaktif) request diteruskan.
+func Require(permissions ...string) fiber.Handler {
+	return require(permissions, false)
+}
+
+// RequireAllYards sama dengan Require, ditambah identitas harus punya akses ke semua yard. Dipakai untuk operasi
+// yang tidak terikat satu yard, contoh: import layout dan tarif penumpukan.
+func RequireAllYards(permissions ...string) fiber.Handler {
+	return require(permissions, true)
+}
+
+func require(permissions []string, allYards bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		p := FromContext(c.UserContext())
+		if p == nil {
+			return c.Next()
+		}
+
+		if err := authorize(c, p, permissions, allYards); err != nil {
+			utils.ApiResponse(c, http.StatusForbidden, "Forbidden", nil, err.Error())
+			return nil
+		}
+		return c.Next()
+	}
+}
+
+func authorize(c *fiber.Ctx, p *Principal, permissions []string, allYards bool) error {
+	for _, permission := range permissions {
+		if !p.Can(permission) {
+			return fmt.Errorf("%s does not have permission %s", p, permission)
+		}
+	}
+	if allYards {
+		if !p.CanAccessYard(AllYards) {
+			return fmt.Errorf("%s is not allowed to access all yards", p)
+		}
+		return nil
+	}
+	if c.Method() != fiber.MethodGet {
+		if yard, _ := requestTarget(c); yard != "" && !p.CanAccessYard(yard) {
+			return fmt.Errorf("%s is not allowed to access yard %s", p, yard)
+		}
+	}
+	return nil
+}
+
+// requestTarget membaca yard dan nomor kontainer yang disebut request dari parameter path, query atau body JSON
+func requestTarget(c *fiber.Ctx) (yard, containerNumber string) {
+	var body struct {
+		Yard            string `json:"yard"`
+		ContainerNumber string `json:"container_number"`
+	}
+	if c.Is("json") {
+		// Body yang tidak valid diabaikan di sini, handler yang mengembalikan 400
+		_ = json.Unmarshal(c.Body(), &body)
+	}
+	yard = firstNonEmpty(c.Params("yard"), c.Query("yard"), body.Yard)
+	containerNumber = firstNonEmpty(c.Params("number"), body.ContainerNumber)
+	// Parameter dan query fiber memakai buffer yang dipakai ulang antar request
+	return strings.Clone(yard), strings.Clone(containerNumber)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,248 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"yard-calculation/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var (
+	gateClerkYRD1  = &Principal{Kind: KindAPIKey, Name: "gate-yrd1", Roles: []string{RoleGateClerk}, Yards: []string{"YRD1"}}
+	plannerYRD1    = &Principal{Kind: KindUser, Name: "budi", Roles: []string{RolePlanner}, Yards: []string{"YRD1"}}
+	plannerAll     = &Principal{Kind: KindUser, Name: "sari", Roles: []string{RolePlanner}, Yards: []string{AllYards}}
+	supervisorBoth = &Principal{Kind: KindUser, Name: "andi", Roles: []string{RoleSupervisor}, Yards: []string{"YRD1", "YRD2"}}
+	supervisorAll  = &Principal{Kind: KindAPIKey, Name: "ops", Roles: []string{RoleSupervisor}, Yards: []string{AllYards}}
+	multiRole      = &Principal{Kind: KindUser, Name: "dewi", Roles: []string{RolePlanner, RoleGateClerk}, Yards: []string{"YRD2"}}
+	noRole         = &Principal{Kind: KindAPIKey, Name: "legacy", Yards: []string{}}
+)
+
+// newTestApp membuat app dengan route seperti main.go. Identitas diisi langsung ke context, menggantikan Middleware.
+func newTestApp(p *Principal, before ...fiber.Handler) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		if p != nil {
+			c.SetUserContext(WithPrincipal(c.UserContext(), p))
+		}
+		return c.Next()
+	})
+	for _, h := range before {
+		app.Use(h)
+	}
+	ok := func(c *fiber.Ctx) error { return c.SendString("ok") }
+	app.Get("/containers", Require(PermView), ok)
+	app.Post("/suggestion", Require(PermSuggest), ok)
+	app.Post("/placement", Require(PermPlace), ok)
+	app.Post("/pickup", Require(PermPickup), ok)
+	app.Post("/holds", Require(PermHold), ok)
+	app.Post("/jobs/shift", Require(PermMove), ok)
+	app.Post("/yards/:yard/blocks/:block/housekeeping", Require(PermPlan), ok)
+	app.Post("/layout/import", RequireAllYards(PermPlan), ok)
+	app.Get("/audit-log", Require(PermAudit), ok)
+	return app
+}
+
+func doRequest(t *testing.T, app *fiber.App, method, target, body string) (int, string) {
+	t.Helper()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, target, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(data)
+}
+
+func TestRequire(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *Principal
+		method    string
+		target    string
+		body      string
+		want      int
+		wantError string
+	}{
+		// Gate clerk YRD1
+		{"gate clerk picks up in own yard", gateClerkYRD1, "POST", "/pickup", `{"yard":"YRD1","container_number":"MSKU0000001"}`, 200, ""},
+		{"gate clerk picks up in other yard", gateClerkYRD1, "POST", "/pickup", `{"yard":"YRD2","container_number":"MSKU0000001"}`, 403, "api_key:gate-yrd1 is not allowed to access yard YRD2"},
+		{"gate clerk places in other yard", gateClerkYRD1, "POST", "/placement", `{"yard":"YRD2"}`, 403, "api_key:gate-yrd1 is not allowed to access yard YRD2"},
+		{"gate clerk yard from query", gateClerkYRD1, "POST", "/pickup?yard=YRD2", `{"container_number":"MSKU0000001"}`, 403, "api_key:gate-yrd1 is not allowed to access yard YRD2"},
+		{"query takes precedence over body", gateClerkYRD1, "POST", "/pickup?yard=YRD2", `{"yard":"YRD1"}`, 403, "api_key:gate-yrd1 is not allowed to access yard YRD2"},
+		{"gate clerk suggests", gateClerkYRD1, "POST", "/suggestion", `{"yard":"YRD1"}`, 200, ""},
+		{"gate clerk cannot hold", gateClerkYRD1, "POST", "/holds", `{"container_number":"MSKU0000001"}`, 403, "api_key:gate-yrd1 does not have permission hold"},
+		{"gate clerk cannot shift", gateClerkYRD1, "POST", "/jobs/shift", `{"yard":"YRD1"}`, 403, "api_key:gate-yrd1 does not have permission move"},
+		{"gate clerk cannot plan", gateClerkYRD1, "POST", "/yards/YRD1/blocks/A/housekeeping", "", 403, "api_key:gate-yrd1 does not have permission plan"},
+		{"gate clerk reads other yard", gateClerkYRD1, "GET", "/containers?yard=YRD2", "", 200, ""},
+		{"gate clerk cannot read audit log", gateClerkYRD1, "GET", "/audit-log", "", 403, "api_key:gate-yrd1 does not have permission audit"},
+		{"invalid body is left to the handler", gateClerkYRD1, "POST", "/pickup", `{"yard":`, 200, ""},
+
+		// Planner
+		{"planner housekeeping in own yard", plannerYRD1, "POST", "/yards/YRD1/blocks/A/housekeeping", "", 200, ""},
+		{"planner yard from path", plannerYRD1, "POST", "/yards/YRD2/blocks/A/housekeeping", "", 403, "user:budi is not allowed to access yard YRD2"},
+		{"planner cannot pick up", plannerYRD1, "POST", "/pickup", `{"yard":"YRD1"}`, 403, "user:budi does not have permission pickup"},
+		{"planner without all yards imports layout", plannerYRD1, "POST", "/layout/import", "", 403, "user:budi is not allowed to access all yards"},
+		{"planner with all yards imports layout", plannerAll, "POST", "/layout/import", "", 200, ""},
+
+		// Supervisor
+		{"supervisor of two yards picks up", supervisorBoth, "POST", "/pickup", `{"yard":"YRD2"}`, 200, ""},
+		{"supervisor of two yards in third yard", supervisorBoth, "POST", "/jobs/shift", `{"yard":"YRD3"}`, 403, "user:andi is not allowed to access yard YRD3"},
+		{"supervisor of two yards imports layout", supervisorBoth, "POST", "/layout/import", "", 403, "user:andi is not allowed to access all yards"},
+		{"supervisor of all yards holds", supervisorAll, "POST", "/holds", `{"container_number":"MSKU0000001"}`, 200, ""},
+		{"supervisor of all yards imports layout", supervisorAll, "POST", "/layout/import", "", 200, ""},
+		{"supervisor reads audit log", supervisorAll, "GET", "/audit-log", "", 200, ""},
+
+		// Beberapa role dan tanpa role
+		{"permissions of all roles are combined", multiRole, "POST", "/yards/YRD2/blocks/A/housekeeping", "", 200, ""},
+		{"yards apply to all roles", multiRole, "POST", "/pickup", `{"yard":"YRD1"}`, 403, "user:dewi is not allowed to access yard YRD1"},
+		{"key without role cannot read", noRole, "GET", "/containers", "", 403, "api_key:legacy does not have permission view"},
+
+		// Autentikasi nonaktif
+		{"no principal is allowed", nil, "POST", "/layout/import", "", 200, ""},
+		{"no principal other yard", nil, "POST", "/pickup", `{"yard":"YRD2"}`, 200, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := doRequest(t, newTestApp(tt.principal), tt.method, tt.target, tt.body)
+			if status != tt.want {
+				t.Fatalf("status = %d, want %d (body %s)", status, tt.want, body)
+			}
+			if tt.wantError != "" {
+				var resp struct {
+					Error string `json:"error"`
+				}
+				if err := json.Unmarshal([]byte(body), &resp); err != nil {
+					t.Fatalf("decode %s: %v", body, err)
+				}
+				if resp.Error != tt.wantError {
+					t.Errorf("error = %q, want %q", resp.Error, tt.wantError)
+				}
+			}
+		})
+	}
+}
+
+func TestRequestTarget(t *testing.T) {
+	tests := []struct {
+		name          string
+		target        string
+		contentType   string
+		body          string
+		wantYard      string
+		wantContainer string
+	}{
+		{"path", "/yards/YRD1/containers/MSKU0000001", "", "", "YRD1", "MSKU0000001"},
+		{"path before query and body", "/yards/YRD1/containers/MSKU0000001?yard=YRD2", fiber.MIMEApplicationJSON, `{"yard":"YRD3","container_number":"CMAU0000002"}`, "YRD1", "MSKU0000001"},
+		{"query before body", "/target?yard=YRD2", fiber.MIMEApplicationJSON, `{"yard":"YRD3","container_number":"CMAU0000002"}`, "YRD2", "CMAU0000002"},
+		{"body", "/target", fiber.MIMEApplicationJSONCharsetUTF8, `{"yard":"YRD3","container_number":"CMAU0000002"}`, "YRD3", "CMAU0000002"},
+		{"body is only read as json", "/target", fiber.MIMETextPlain, `{"yard":"YRD3"}`, "", ""},
+		{"invalid json", "/target", fiber.MIMEApplicationJSON, `{"yard":`, "", ""},
+		{"nothing", "/target", "", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var yard, containerNumber string
+			app := fiber.New()
+			capture := func(c *fiber.Ctx) error {
+				yard, containerNumber = requestTarget(c)
+				return nil
+			}
+			app.Post("/yards/:yard/containers/:number", capture)
+			app.Post("/target", capture)
+
+			req := httptest.NewRequest("POST", tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set(fiber.HeaderContentType, tt.contentType)
+			}
+			if _, err := app.Test(req); err != nil {
+				t.Fatal(err)
+			}
+			if yard != tt.wantYard || containerNumber != tt.wantContainer {
+				t.Errorf("requestTarget() = (%q, %q), want (%q, %q)", yard, containerNumber, tt.wantYard, tt.wantContainer)
+			}
+		})
+	}
+}
+
+func TestCheckYard(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *Principal
+		yard      string
+		want      string
+	}{
+		{"own yard", gateClerkYRD1, "YRD1", ""},
+		{"other yard", gateClerkYRD1, "YRD2", "api_key:gate-yrd1 is not allowed to access yard YRD2"},
+		{"one of several yards", supervisorBoth, "YRD2", ""},
+		{"all yards", supervisorAll, "YRD9", ""},
+		{"no yards", noRole, "YRD1", "api_key:legacy is not allowed to access yard YRD1"},
+		{"no principal", nil, "YRD2", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = WithPrincipal(ctx, tt.principal)
+			}
+			got := ""
+			if err := CheckYard(ctx, tt.yard); err != nil {
+				got = err.Error()
+			}
+			if got != tt.want {
+				t.Errorf("CheckYard() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// auditRecorder menyimpan catatan audit di memori
+type auditRecorder struct {
+	mu      sync.Mutex
+	entries []models.AuditEntry
+}
+
+func (r *auditRecorder) RecordAudit(_ context.Context, entry *models.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func TestAudit(t *testing.T) {
+	recorder := &auditRecorder{}
+	app := newTestApp(gateClerkYRD1, Audit(recorder, "/suggestion"))
+
+	doRequest(t, app, "GET", "/containers?yard=YRD1", "")
+	doRequest(t, app, "POST", "/suggestion", `{"yard":"YRD1"}`)
+	doRequest(t, app, "POST", "/pickup", `{"yard":"YRD1","container_number":"MSKU0000001"}`)
+	doRequest(t, app, "POST", "/pickup", `{"yard":"YRD2","container_number":"MSKU0000002"}`)
+
+	want := []models.AuditEntry{
+		{User: "api_key:gate-yrd1", Method: "POST", Route: "/pickup", Path: "/pickup", Status: 200, Yard: "YRD1", ContainerNumber: "MSKU0000001"},
+		{User: "api_key:gate-yrd1", Method: "POST", Route: "/pickup", Path: "/pickup", Status: 403, Yard: "YRD2", ContainerNumber: "MSKU0000002"},
+	}
+	if len(recorder.entries) != len(want) {
+		t.Fatalf("recorded %d entries, want %d: %+v", len(recorder.entries), len(want), recorder.entries)
+	}
+	for i := range want {
+		if recorder.entries[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, recorder.entries[i], want[i])
+		}
+	}
+}
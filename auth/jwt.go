@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// clockSkew adalah toleransi perbedaan jam antara penerbit token dan server
+const clockSkew = 30 * time.Second
+
+// Claims adalah isi token pengguna: claim standar ditambah role dan yard tempat pengguna ditugaskan
+type Claims struct {
+	Roles []string `json:"roles"`
+	Yards []string `json:"yards"`
+	jwt.RegisteredClaims
+}
+
+// JWT memverifikasi (dan untuk development, menerbitkan) bearer token HS256 dengan secret bersama. Issuer dan
+// audience hanya dicek jika diisi.
+type JWT struct {
+	secret   []byte
+	issuer   string
+	audience string
+}
+
+func NewJWT(secret, issuer, audience string) *JWT {
+	return &JWT{secret: []byte(secret), issuer: issuer, audience: audience}
+}
+
+// Verify memeriksa tanda tangan, masa berlaku, issuer dan audience token lalu mengembalikan identitas pengguna
+func (j *JWT) Verify(token string) (*Principal, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(clockSkew),
+	}
+	if j.issuer != "" {
+		options = append(options, jwt.WithIssuer(j.issuer))
+	}
+	if j.audience != "" {
+		options = append(options, jwt.WithAudience(j.audience))
+	}
+
+	claims := &Claims{}
+	if _, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return j.secret, nil
+	}, options...); err != nil {
+		return nil, fmt.Errorf("invalid token: %v", err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("invalid token: subject is required")
+	}
+	return &Principal{Kind: KindUser, Name: claims.Subject, Roles: claims.Roles, Yards: claims.Yards}, nil
+}
+
+// Issue menerbitkan token untuk subject dengan role dan yard tertentu dan masa berlaku ttl
+func (j *JWT) Issue(subject string, roles, yards []string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		Roles: roles,
+		Yards: yards,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   subject,
+			Issuer:    j.issuer,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	if j.audience != "" {
+		claims.Audience = jwt.ClaimStrings{j.audience}
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(j.secret)
+}
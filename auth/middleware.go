@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"yard-calculation/logging"
+	"yard-calculation/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HeaderAPIKey dipakai client mesin untuk mengirim API key
+const HeaderAPIKey = "X-API-Key"
+
+// APIKeyAuthenticator mencari identitas pemilik API key, ErrInvalidAPIKey jika kunci tidak dikenal atau dicabut
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*Principal, error)
+}
+
+// Middleware menolak request tanpa kredensial yang valid dengan 401. Kredensial dibaca dari header X-API-Key
+// (client mesin) atau Authorization: Bearer <JWT> (pengguna). tokens nil berarti JWT tidak diterima.
+// Identitas yang lolos disimpan di context request dan ditulis di setiap baris log sebagai field user.
+func Middleware(keys APIKeyAuthenticator, tokens *JWT) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := c.UserContext()
+
+		var principal *Principal
+		var err error
+		if key := c.Get(HeaderAPIKey); key != "" {
+			principal, err = keys.AuthenticateAPIKey(ctx, key)
+			if err != nil && !errors.Is(err, ErrInvalidAPIKey) {
+				utils.ApiResponse(c, http.StatusInternalServerError, "Error Authenticate", nil, err.Error())
+				return nil
+			}
+		} else if token, ok := bearerToken(c.Get(fiber.HeaderAuthorization)); ok {
+			if tokens == nil {
+				err = errors.New("bearer tokens are not enabled")
+			} else {
+				principal, err = tokens.Verify(token)
+			}
+		} else {
+			err = errors.New("missing credentials: send X-API-Key or Authorization: Bearer header")
+		}
+		if err != nil {
+			c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+			utils.ApiResponse(c, http.StatusUnauthorized, "Unauthorized", nil, err.Error())
+			return nil
+		}
+
+		ctx = WithPrincipal(ctx, principal)
+		c.SetUserContext(logging.With(ctx, logging.KeyUser, principal.String()))
+		return c.Next()
+	}
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Role pengguna dan API key
+const (
+	RoleGateClerk  = "gate_clerk" // Petugas gate: saran posisi, penempatan, pickup dan booking truk
+	RolePlanner    = "planner"    // Perencana yard: layout, rencana block, housekeeping dan rencana bongkar kapal
+	RoleSupervisor = "supervisor" // Semua operasi, termasuk hold dan pemindahan kontainer
+)
+
+// Permission yang dicek di setiap route
+const (
+	PermView      = "view"       // Membaca data kontainer, block, statistik, job dan hold
+	PermSuggest   = "suggest"    // Saran posisi tunggal dan batch
+	PermPlace     = "place"      // Penempatan kontainer (gate-in)
+	PermPickup    = "pickup"     // Pickup kontainer (gate-out)
+	PermPreAdvice = "pre_advice" // Membuat dan membatalkan booking truk
+	PermJob       = "job"        // Memulai, menyelesaikan dan menggagalkan job alat
+	PermPlan      = "plan"       // Import layout, housekeeping, rencana bongkar kapal dan alat
+	PermHold      = "hold"       // Menambah dan melepas hold
+	PermMove      = "move"       // Memindahkan (shift) kontainer
+	PermTariff    = "tariff"     // Mengelola tarif penumpukan
+	PermAudit     = "audit"      // Membaca audit log
+)
+
+// AllYards di daftar yard berarti identitas boleh mengakses semua yard
+const AllYards = "*"
+
+var rolePermissions = map[string][]string{
+	RoleGateClerk: {PermView, PermSuggest, PermPlace, PermPickup, PermPreAdvice, PermJob},
+	RolePlanner:   {PermView, PermSuggest, PermPreAdvice, PermPlan},
+	RoleSupervisor: {PermView, PermSuggest, PermPlace, PermPickup, PermPreAdvice, PermJob, PermPlan, PermHold, PermMove,
+		PermTariff, PermAudit},
+}
+
+// ValidRole mengecek apakah role dikenal
+func ValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// Roles mengembalikan semua role yang dikenal, urut abjad
+func Roles() []string {
+	roles := make([]string, 0, len(rolePermissions))
+	for role := range rolePermissions {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// ParseList memecah daftar role atau yard yang dipisah koma, tanpa spasi, nilai kosong dan duplikat
+func ParseList(value string) []string {
+	result := []string{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" && !slices.Contains(result, item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Can mengecek apakah salah satu role identitas memberi permission tersebut
+func (p *Principal) Can(permission string) bool {
+	for _, role := range p.Roles {
+		if slices.Contains(rolePermissions[role], permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccessYard mengecek apakah identitas ditugaskan di yard tersebut
+func (p *Principal) CanAccessYard(yard string) bool {
+	return slices.Contains(p.Yards, AllYards) || slices.Contains(p.Yards, yard)
+}
+
+// CheckYard mengembalikan error jika identitas di ctx tidak boleh mengakses yard. Dipakai service untuk operasi
+// yang yard-nya baru diketahui dari database (contoh: menyelesaikan job berdasarkan ID). Tanpa identitas (CLI,
+// proses background atau autentikasi nonaktif) selalu diizinkan.
+func CheckYard(ctx context.Context, yard string) error {
+	p := FromContext(ctx)
+	if p == nil || p.CanAccessYard(yard) {
+		return nil
+	}
+	return fmt.Errorf("%s is not allowed to access yard %s", p, yard)
+}
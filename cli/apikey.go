@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"yard-calculation/auth"
+	"yard-calculation/config"
+	"yard-calculation/repositories"
+	"yard-calculation/services"
+)
+
+// APIKey membuat, mencabut atau menampilkan API key untuk client mesin.
+// Contoh: go run main.go apikey create -name gate-yrd1 -role gate_clerk -yards YRD1,
+// go run main.go apikey revoke -name gate-yrd1,
+// go run main.go apikey list
+func APIKey(cfg *config.Config, args []string) error {
+	action := "list"
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		action, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet("apikey", flag.ExitOnError)
+	name := fs.String("name", "", "api key name, for example the gate system it belongs to (create and revoke)")
+	role := fs.String("role", "", "role of the key owner (create): "+strings.Join(auth.Roles(), ", "))
+	yards := fs.String("yards", "", "comma separated yards the key may access (create), "+auth.AllYards+" for all yards")
+	fs.Parse(args)
+
+	ctx := context.Background()
+	service := services.NewAuthService(repositories.NewAPIKeyRepository(config.DB))
+	switch action {
+	case "create":
+		if *name == "" {
+			return fmt.Errorf("-name is required")
+		}
+		key, apiKey, err := service.CreateAPIKey(ctx, *name, *role, auth.ParseList(*yards))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("created api key %s (role %s, yards %s)\n", apiKey.Name, apiKey.Role, apiKey.Yards)
+		fmt.Printf("key: %s\n", key)
+		fmt.Println("store this key now, it cannot be shown again")
+		return nil
+	case "revoke":
+		if *name == "" {
+			return fmt.Errorf("-name is required")
+		}
+		apiKey, err := service.RevokeAPIKey(ctx, *name)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("revoked api key %s\n", apiKey.Name)
+		return nil
+	case "list":
+		keys, err := service.ListAPIKeys(ctx)
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			state := "active"
+			if k.RevokedAt != nil {
+				state = "revoked " + k.RevokedAt.Format("2006-01-02 15:04:05")
+			}
+			lastUsed := "never used"
+			if k.LastUsedAt != nil {
+				lastUsed = "last used " + k.LastUsedAt.Format("2006-01-02 15:04:05")
+			}
+			role := k.Role
+			if role == "" {
+				role = "no role (re-issue)"
+			}
+			fmt.Printf("%s\t%s...\t%s\t%s\t%s\t%s\n", k.Name, k.Prefix, role, k.Yards, state, lastUsed)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown apikey action %q, expected create, revoke or list", action)
+	}
+}
@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"yard-calculation/config"
+)
+
+// commands berisi subcommand yang bisa dijalankan lewat `go run main.go <command> [flags]`
+var commands = map[string]func(cfg *config.Config, args []string) error{
+	"import":        ImportLayout,
+	"codeco":        IngestCodeco,
+	"codeco-replay": ReplayCodeco,
+	"migrate":       Migrate,
+	"apikey":        APIKey,
+	"token":         IssueToken,
+}
+
+// Run menjalankan subcommand, database harus sudah terkoneksi
+func Run(cfg *config.Config, name string, args []string) error {
+	command, ok := commands[name]
+	if !ok {
+		names := make([]string, 0, len(commands))
+		for n := range commands {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown command %q, available commands: %s", name, strings.Join(names, ", "))
+	}
+	return command(cfg, args)
+}
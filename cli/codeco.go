@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"yard-calculation/config"
+	"yard-calculation/services"
+)
+
+// IngestCodeco memproses file CODECO dari gate system.
+// Contoh: go run main.go codeco -yard YRD1 -file gate.edi
+func IngestCodeco(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("codeco", flag.ExitOnError)
+	yard := fs.String("yard", "", "yard where the gate events happened")
+	file := fs.String("file", "", "CODECO file")
+	fs.Parse(args)
+
+	if *yard == "" || *file == "" {
+		return fmt.Errorf("-yard and -file are required")
+	}
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+
+	// Pakai service yang sama dengan server: CODECO keluar, pesanan posisi, hold dan job yang masih terbuka tetap berlaku
+	service := services.NewContainerServices(config.DB, cfg, nil).Codeco
+	result, err := service.IngestCodeco(context.Background(), *yard, string(data))
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+
+	if len(result.Errors) > 0 {
+		return fmt.Errorf("%d segment(s) could not be applied", len(result.Errors))
+	}
+	return nil
+}
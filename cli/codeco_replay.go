@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+	"yard-calculation/config"
+	"yard-calculation/repositories"
+	"yard-calculation/services"
+)
+
+// ReplayCodeco menulis ulang CODECO yang belum terkirim sejak tanggal tertentu.
+// Contoh: go run main.go codeco-replay -since 2024-01-01
+func ReplayCodeco(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("codeco-replay", flag.ExitOnError)
+	since := fs.String("since", "", "replay events since this date (YYYY-MM-DD)")
+	fs.Parse(args)
+
+	sinceTime, err := time.Parse(time.DateOnly, *since)
+	if err != nil {
+		return fmt.Errorf("-since must be a date in format YYYY-MM-DD")
+	}
+
+	service := services.NewCodecoOutboxService(repositories.NewEDIOutboxRepository(config.DB), repositories.NewContainerRepository(config.DB), cfg.EDI)
+
+	written, err := service.Replay(context.Background(), sinceTime)
+	fmt.Printf("%d CODECO message(s) written to %s\n", written, cfg.EDI.OutboxDir)
+	return err
+}
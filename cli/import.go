@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"yard-calculation/config"
+	"yard-calculation/repositories"
+	"yard-calculation/services"
+)
+
+// ImportLayout mengimport yard, block dan rencana dari CSV/XLSX.
+// Contoh: go run main.go import -file layout.xlsx -dry-run
+func ImportLayout(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	file := fs.String("file", "", "XLSX file with yards, blocks and plans sheets")
+	yards := fs.String("yards", "", "CSV or XLSX file with yards")
+	blocks := fs.String("blocks", "", "CSV or XLSX file with blocks")
+	plans := fs.String("plans", "", "CSV or XLSX file with plans")
+	dryRun := fs.Bool("dry-run", false, "validate only, do not save anything")
+	fs.Parse(args)
+
+	tables := services.LayoutTables{}
+	found := false
+	for _, input := range []struct{ kind, path string }{
+		{"file", *file}, {"yards", *yards}, {"blocks", *blocks}, {"plans", *plans},
+	} {
+		if input.path == "" {
+			continue
+		}
+		f, err := os.Open(input.path)
+		if err != nil {
+			return err
+		}
+		err = tables.ReadFile(input.kind, input.path, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		found = true
+	}
+	if !found {
+		return fmt.Errorf("at least one of -file, -yards, -blocks or -plans is required")
+	}
+
+	service := services.NewLayoutImportService(repositories.NewContainerRepository(config.DB))
+	result, err := service.ImportLayout(context.Background(), tables, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+
+	if !result.Valid {
+		return fmt.Errorf("layout file has %d error(s), nothing was imported", len(result.Errors))
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"yard-calculation/config"
+	"yard-calculation/migrations"
+)
+
+// Migrate menjalankan, membatalkan atau menampilkan status migrasi skema database.
+// Contoh: go run main.go migrate up, go run main.go migrate rollback -steps 1, go run main.go migrate status
+func Migrate(cfg *config.Config, args []string) error {
+	action := "up"
+	if len(args) > 0 && args[0] != "" && args[0][0] != '-' {
+		action, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	steps := fs.Int("steps", 1, "number of migrations to roll back (rollback only)")
+	fs.Parse(args)
+
+	switch action {
+	case "up":
+		done, err := migrations.Up(config.DB)
+		for _, m := range done {
+			fmt.Printf("applied %s_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(done) == 0 {
+			fmt.Println("database is up to date")
+		}
+		return err
+	case "rollback":
+		done, err := migrations.Rollback(config.DB, *steps)
+		for _, m := range done {
+			fmt.Printf("rolled back %s_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(done) == 0 {
+			fmt.Println("no migrations to roll back")
+		}
+		return err
+	case "status":
+		statuses, err := migrations.GetStatus(config.DB)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.AppliedAt != nil {
+				state = "applied " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if s.Unknown {
+				state += " (unknown to this version)"
+			}
+			fmt.Printf("%s_%s\t%s\n", s.Version, s.Name, state)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown migrate action %q, expected up, rollback or status", action)
+	}
+}
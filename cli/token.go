@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+	"yard-calculation/auth"
+	"yard-calculation/config"
+)
+
+// IssueToken menerbitkan JWT pengguna dengan AUTH_JWT_SECRET, untuk development atau selama belum ada identity
+// provider yang menerbitkan token.
+// Contoh: go run main.go token -sub budi -roles gate_clerk -yards YRD1 -ttl 8h
+func IssueToken(cfg *config.Config, args []string) error {
+	fs := flag.NewFlagSet("token", flag.ExitOnError)
+	subject := fs.String("sub", "", "user name written to the token subject")
+	roles := fs.String("roles", "", "comma separated roles: "+strings.Join(auth.Roles(), ", "))
+	yards := fs.String("yards", "", "comma separated yards the user works at, "+auth.AllYards+" for all yards")
+	ttl := fs.Duration("ttl", 8*time.Hour, "token lifetime")
+	fs.Parse(args)
+
+	if *subject == "" {
+		return fmt.Errorf("-sub is required")
+	}
+	roleList := auth.ParseList(*roles)
+	if len(roleList) == 0 {
+		return fmt.Errorf("-roles is required")
+	}
+	for _, role := range roleList {
+		if !auth.ValidRole(role) {
+			return fmt.Errorf("invalid role %q, expected %s", role, strings.Join(auth.Roles(), ", "))
+		}
+	}
+	yardList := auth.ParseList(*yards)
+	if len(yardList) == 0 {
+		return fmt.Errorf("-yards is required")
+	}
+	if cfg.Auth.JWTSecret == "" {
+		return fmt.Errorf("AUTH_JWT_SECRET is not set")
+	}
+	token, err := auth.NewJWT(cfg.Auth.JWTSecret, cfg.Auth.JWTIssuer, cfg.Auth.JWTAudience).Issue(*subject, roleList, yardList, *ttl)
+	if err != nil {
+		return err
+	}
+	fmt.Println(token)
+	return nil
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+type AlertConfig struct {
+	// Persentase terisi di mana sebuah rencana dianggap hampir penuh
+	Threshold float64 `yaml:"threshold"`
+	// Interval pengecekan berkala, 0 berarti tidak dijalankan
+	Interval time.Duration `yaml:"interval"`
+	// URL webhook tujuan notifikasi, kosong berarti hanya ditulis ke log
+	WebhookURL string `yaml:"webhook_url"`
+}
+
+func (c *AlertConfig) loadEnv(e *envReader) {
+	e.float("PLAN_ALERT_THRESHOLD", &c.Threshold)
+	e.duration("PLAN_ALERT_INTERVAL", &c.Interval)
+	e.string("PLAN_ALERT_WEBHOOK_URL", &c.WebhookURL)
+}
+
+func (c AlertConfig) validate() []string {
+	errs := []string{}
+	if c.Threshold <= 0 || c.Threshold > 100 {
+		errs = append(errs, fmt.Sprintf("alert.threshold must be between 0 and 100, got %g", c.Threshold))
+	}
+	if c.Interval < 0 {
+		errs = append(errs, fmt.Sprintf("alert.interval must not be negative, got %s", c.Interval))
+	}
+	return errs
+}
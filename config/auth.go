@@ -0,0 +1,34 @@
+package config
+
+import "fmt"
+
+// minJWTSecretLength adalah panjang minimum secret HS256 (256 bit)
+const minJWTSecretLength = 32
+
+type AuthConfig struct {
+	// Jika nonaktif semua endpoint terbuka tanpa kredensial, hanya untuk development dan test
+	Enabled bool `yaml:"enabled"`
+	// Secret bersama untuk memverifikasi JWT HS256, kosong berarti hanya API key yang diterima
+	JWTSecret string `yaml:"jwt_secret"`
+	// Jika diisi, claim iss dan aud token harus sama dengan nilai ini
+	JWTIssuer   string `yaml:"jwt_issuer"`
+	JWTAudience string `yaml:"jwt_audience"`
+}
+
+func (c *AuthConfig) loadEnv(e *envReader) {
+	e.bool("AUTH_ENABLED", &c.Enabled)
+	e.string("AUTH_JWT_SECRET", &c.JWTSecret)
+	e.string("AUTH_JWT_ISSUER", &c.JWTIssuer)
+	e.string("AUTH_JWT_AUDIENCE", &c.JWTAudience)
+}
+
+func (c AuthConfig) validate(env string) []string {
+	errs := []string{}
+	if !c.Enabled && env == EnvProduction {
+		errs = append(errs, "auth.enabled must be true in production")
+	}
+	if c.JWTSecret != "" && len(c.JWTSecret) < minJWTSecretLength {
+		errs = append(errs, fmt.Sprintf("auth.jwt_secret must be at least %d characters, got %d", minJWTSecretLength, len(c.JWTSecret)))
+	}
+	return errs
+}
@@ -0,0 +1,324 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Profil environment, dipilih lewat APP_ENV
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+	EnvTest        = "test"
+)
+
+// Config adalah seluruh konfigurasi aplikasi. Urutan sumber: nilai default, file YAML (opsional), lalu environment
+// variable. Environment variable selalu menang supaya nilai di file bisa ditimpa saat deploy.
+type Config struct {
+	Env       string          `yaml:"env"`
+	HTTP      HTTPConfig      `yaml:"http"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Log       LogConfig       `yaml:"log"`
+	Auth      AuthConfig      `yaml:"auth"`
+	Features  FeatureConfig   `yaml:"features"`
+	Alert     AlertConfig     `yaml:"alert"`
+	EDI       EDIConfig       `yaml:"edi"`
+	PreAdvice PreAdviceConfig `yaml:"pre_advice"`
+	Metrics   MetricsConfig   `yaml:"metrics"`
+}
+
+type HTTPConfig struct {
+	Port int `yaml:"port"`
+	// Batas waktu menunggu request yang sedang berjalan dan menutup pool database saat SIGTERM
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// Batas waktu setiap request, query database dan pencarian posisi dihentikan setelah lewat
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+	// Batas waktu per route dengan key "METHOD /pola/route" (contoh: "POST /suggestion/batch"), menimpa
+	// request_timeout untuk route tersebut
+	RouteTimeouts map[string]time.Duration `yaml:"route_timeouts"`
+	// Origin yang boleh memanggil API dari browser, contoh: https://planner.example.com. Kosong berarti request
+	// lintas origin dari browser ditolak.
+	CORSOrigins []string `yaml:"cors_origins"`
+}
+
+// Addr mengembalikan alamat listen untuk fiber, contoh: :3003
+func (c HTTPConfig) Addr() string {
+	return fmt.Sprintf(":%d", c.Port)
+}
+
+// Timeout mengembalikan batas waktu untuk route, dari route_timeouts jika ada atau request_timeout
+func (c HTTPConfig) Timeout(method, route string) time.Duration {
+	if d, ok := c.RouteTimeouts[method+" "+route]; ok {
+		return d
+	}
+	return c.RequestTimeout
+}
+
+func (c HTTPConfig) validate() []string {
+	errs := []string{}
+	if c.Port < 1 || c.Port > 65535 {
+		errs = append(errs, fmt.Sprintf("http.port must be between 1 and 65535, got %d", c.Port))
+	}
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, fmt.Sprintf("http.shutdown_timeout must be positive, got %s", c.ShutdownTimeout))
+	}
+	if c.RequestTimeout <= 0 {
+		errs = append(errs, fmt.Sprintf("http.request_timeout must be positive, got %s", c.RequestTimeout))
+	}
+	for _, origin := range c.CORSOrigins {
+		if origin != "*" && ((!strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://")) || strings.HasSuffix(origin, "/")) {
+			errs = append(errs, fmt.Sprintf("http.cors_origins must contain * or origins such as https://planner.example.com without a trailing slash, got %q", origin))
+		}
+	}
+	for route, d := range c.RouteTimeouts {
+		method, path, ok := strings.Cut(route, " ")
+		if !ok || method == "" || method != strings.ToUpper(method) || !strings.HasPrefix(path, "/") {
+			errs = append(errs, fmt.Sprintf("http.route_timeouts key must look like \"POST /suggestion/batch\", got %q", route))
+		}
+		if d <= 0 {
+			errs = append(errs, fmt.Sprintf("http.route_timeouts[%q] must be positive, got %s", route, d))
+		}
+	}
+	return errs
+}
+
+// Level log yang didukung
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+type LogConfig struct {
+	// debug juga menulis semua query SQL
+	Level string `yaml:"level"`
+}
+
+func defaultConfig() Config {
+	return Config{
+		Env:  EnvDevelopment,
+		HTTP: HTTPConfig{Port: 3003, ShutdownTimeout: 30 * time.Second, RequestTimeout: 30 * time.Second},
+		Database: DatabaseConfig{
+			Driver:          DriverPostgres,
+			Port:            5432,
+			Path:            "yard.db",
+			SSLMode:         "disable",
+			TimeZone:        "Asia/Shanghai",
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 30 * time.Minute,
+		},
+		Log:       LogConfig{Level: LogLevelInfo},
+		Auth:      AuthConfig{Enabled: true},
+		Features:  FeatureConfig{WorkQueue: true, PreAdviceReservation: true, HoldCheck: true},
+		Alert:     AlertConfig{Threshold: 80},
+		EDI:       EDIConfig{OutboxDir: "outbox"},
+		PreAdvice: PreAdviceConfig{Grace: 2 * time.Hour},
+		Metrics:   MetricsConfig{OccupancyInterval: 30 * time.Second},
+	}
+}
+
+// Load membaca .env, file YAML dan environment variable lalu memvalidasi hasilnya. File YAML diambil dari
+// CONFIG_FILE, atau config.<APP_ENV>.yaml jika file itu ada (contoh: config.production.yaml).
+func Load() (*Config, error) {
+	// .env opsional, environment variable dari sistem tetap dipakai jika file tidak ada
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("error loading .env file: %v", err)
+	}
+
+	cfg := defaultConfig()
+	if env := os.Getenv("APP_ENV"); env != "" {
+		cfg.Env = env
+	}
+
+	path := os.Getenv("CONFIG_FILE")
+	if path == "" {
+		profilePath := fmt.Sprintf("config.%s.yaml", cfg.Env)
+		if _, err := os.Stat(profilePath); err == nil {
+			path = profilePath
+		}
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+		// APP_ENV tetap menang atas env di file
+		if env := os.Getenv("APP_ENV"); env != "" {
+			cfg.Env = env
+		}
+	}
+
+	e := &envReader{}
+	cfg.loadEnv(e)
+
+	errs := append(e.errs, cfg.Validate()...)
+	errs = append(errs, cfg.EDI.loadPartners()...)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n  %s", strings.Join(errs, "\n  "))
+	}
+	return &cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %v", err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	// Key yang salah ketik ditolak, bukan diabaikan diam-diam
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("error parsing config file %s: %v", path, err)
+	}
+	return nil
+}
+
+func (c *Config) loadEnv(e *envReader) {
+	e.int("HTTP_PORT", &c.HTTP.Port)
+	e.duration("HTTP_SHUTDOWN_TIMEOUT", &c.HTTP.ShutdownTimeout)
+	e.duration("HTTP_REQUEST_TIMEOUT", &c.HTTP.RequestTimeout)
+	e.durationMap("HTTP_ROUTE_TIMEOUTS", &c.HTTP.RouteTimeouts)
+	e.list("HTTP_CORS_ORIGINS", &c.HTTP.CORSOrigins)
+	e.string("LOG_LEVEL", &c.Log.Level)
+	c.Database.loadEnv(e)
+	c.Auth.loadEnv(e)
+	c.Features.loadEnv(e)
+	c.Alert.loadEnv(e)
+	c.EDI.loadEnv(e)
+	c.PreAdvice.loadEnv(e)
+	c.Metrics.loadEnv(e)
+}
+
+// Validate mengembalikan semua kesalahan konfigurasi sekaligus, bukan hanya yang pertama
+func (c *Config) Validate() []string {
+	errs := []string{}
+	switch c.Env {
+	case EnvDevelopment, EnvProduction, EnvTest:
+	default:
+		errs = append(errs, fmt.Sprintf("env must be one of %s, %s or %s, got %q", EnvDevelopment, EnvProduction, EnvTest, c.Env))
+	}
+	if c.EDI.OutboxDir == "" {
+		errs = append(errs, "edi.outbox_dir must not be empty")
+	}
+	errs = append(errs, c.HTTP.validate()...)
+	switch c.Log.Level {
+	case LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError:
+	default:
+		errs = append(errs, fmt.Sprintf("log.level must be one of %s, %s, %s or %s, got %q", LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError, c.Log.Level))
+	}
+	errs = append(errs, c.Database.validate()...)
+	errs = append(errs, c.Auth.validate(c.Env)...)
+	errs = append(errs, c.Alert.validate()...)
+	errs = append(errs, c.PreAdvice.validate()...)
+	errs = append(errs, c.Metrics.validate()...)
+	return errs
+}
+
+// envReader membaca environment variable ke field konfigurasi. Variable yang tidak diset dibiarkan,
+// nilai yang tidak bisa di-parse dikumpulkan sebagai error.
+type envReader struct {
+	errs []string
+}
+
+func (e *envReader) lookup(name string) (string, bool) {
+	v, ok := os.LookupEnv(name)
+	if !ok || v == "" {
+		return "", false
+	}
+	return v, true
+}
+
+func (e *envReader) string(name string, dst *string) {
+	if v, ok := e.lookup(name); ok {
+		*dst = v
+	}
+}
+
+func (e *envReader) int(name string, dst *int) {
+	if v, ok := e.lookup(name); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Sprintf("%s must be an integer, got %q", name, v))
+			return
+		}
+		*dst = n
+	}
+}
+
+func (e *envReader) float(name string, dst *float64) {
+	if v, ok := e.lookup(name); ok {
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Sprintf("%s must be a number, got %q", name, v))
+			return
+		}
+		*dst = f
+	}
+}
+
+func (e *envReader) bool(name string, dst *bool) {
+	if v, ok := e.lookup(name); ok {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Sprintf("%s must be true or false, got %q", name, v))
+			return
+		}
+		*dst = b
+	}
+}
+
+func (e *envReader) duration(name string, dst *time.Duration) {
+	if v, ok := e.lookup(name); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Sprintf("%s must be a duration such as 30s, 15m or 2h, got %q", name, v))
+			return
+		}
+		*dst = d
+	}
+}
+
+// list membaca daftar yang dipisah koma, contoh: "https://a.example.com,https://b.example.com"
+func (e *envReader) list(name string, dst *[]string) {
+	if v, ok := e.lookup(name); ok {
+		items := []string{}
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		*dst = items
+	}
+}
+
+// durationMap membaca daftar "key=durasi" yang dipisah koma, contoh: "POST /suggestion/batch=2m,POST /layout/import=5m".
+// Key dari environment variable menimpa key yang sama dari file, key lain tetap dipakai.
+func (e *envReader) durationMap(name string, dst *map[string]time.Duration) {
+	v, ok := e.lookup(name)
+	if !ok {
+		return
+	}
+	if *dst == nil {
+		*dst = make(map[string]time.Duration)
+	}
+	for _, item := range strings.Split(v, ",") {
+		key, value, ok := strings.Cut(item, "=")
+		key = strings.TrimSpace(key)
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if !ok || key == "" || err != nil {
+			e.errs = append(e.errs, fmt.Sprintf("%s must be a list such as \"POST /suggestion/batch=2m,POST /layout/import=5m\", got %q", name, item))
+			continue
+		}
+		(*dst)[key] = d
+	}
+}
@@ -2,36 +2,169 @@ package config
 
 import (
 	"fmt"
-	"os"
+	"log/slog"
+	"slices"
+	"time"
 
-	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 var DB *gorm.DB
 
-func ConnectDatabase() {
-	// Load environment variables from .env file
-	err := godotenv.Load()
-	if err != nil {
-		fmt.Println("Error loading .env file")
-		// Atau panic, tergantung kebijakan error handling kamu
+// Driver database yang didukung, dipilih lewat DB_DRIVER
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// sslmode yang diterima PostgreSQL
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+type DatabaseConfig struct {
+	Driver   string `yaml:"driver"` // postgres (default) atau sqlite
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Name     string `yaml:"name"`
+	SSLMode  string `yaml:"sslmode"`
+	// Timezone sesi PostgreSQL, nama dari database IANA, contoh: Asia/Jakarta
+	TimeZone string `yaml:"timezone"`
+	// File database SQLite, atau :memory:
+	Path string `yaml:"path"`
+	// Pengaturan pool koneksi, 0 berarti tanpa batas
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+	// Jika aktif, server menjalankan migrasi yang belum dijalankan saat start; jika tidak, server menolak start
+	// selama masih ada migrasi yang belum dijalankan
+	MigrateOnStart bool `yaml:"migrate_on_start"`
+}
+
+func (c *DatabaseConfig) loadEnv(e *envReader) {
+	e.string("DB_DRIVER", &c.Driver)
+	e.string("DB_HOST", &c.Host)
+	e.int("DB_PORT", &c.Port)
+	e.string("DB_USER", &c.User)
+	e.string("DB_PASSWORD", &c.Password)
+	e.string("DB_NAME", &c.Name)
+	e.string("DB_SSLMODE", &c.SSLMode)
+	e.string("DB_TIMEZONE", &c.TimeZone)
+	e.string("DB_PATH", &c.Path)
+	e.int("DB_MAX_OPEN_CONNS", &c.MaxOpenConns)
+	e.int("DB_MAX_IDLE_CONNS", &c.MaxIdleConns)
+	e.duration("DB_CONN_MAX_LIFETIME", &c.ConnMaxLifetime)
+	e.duration("DB_CONN_MAX_IDLE_TIME", &c.ConnMaxIdleTime)
+	e.bool("DB_MIGRATE_ON_START", &c.MigrateOnStart)
+}
+
+func (c DatabaseConfig) validate() []string {
+	errs := []string{}
+	switch c.Driver {
+	case DriverPostgres:
+		for _, field := range []struct{ name, value string }{{"host", c.Host}, {"user", c.User}, {"name", c.Name}} {
+			if field.value == "" {
+				errs = append(errs, fmt.Sprintf("database.%s is required for driver %s", field.name, DriverPostgres))
+			}
+		}
+		if c.Port < 1 || c.Port > 65535 {
+			errs = append(errs, fmt.Sprintf("database.port must be between 1 and 65535, got %d", c.Port))
+		}
+		if !slices.Contains(sslModes, c.SSLMode) {
+			errs = append(errs, fmt.Sprintf("database.sslmode must be one of %v, got %q", sslModes, c.SSLMode))
+		}
+		if _, err := time.LoadLocation(c.TimeZone); err != nil || c.TimeZone == "" {
+			errs = append(errs, fmt.Sprintf("database.timezone must be an IANA time zone such as Asia/Jakarta, got %q", c.TimeZone))
+		}
+	case DriverSQLite:
+		if !sqliteSupported {
+			errs = append(errs, fmt.Sprintf("database.driver %s requires a binary built with CGO_ENABLED=1 (github.com/mattn/go-sqlite3 needs cgo)", DriverSQLite))
+		}
+		if c.Path == "" {
+			errs = append(errs, fmt.Sprintf("database.path is required for driver %s", DriverSQLite))
+		}
+	default:
+		errs = append(errs, fmt.Sprintf("database.driver must be %s or %s, got %q", DriverPostgres, DriverSQLite, c.Driver))
+	}
+	if c.MaxOpenConns < 0 || c.MaxIdleConns < 0 {
+		errs = append(errs, "database.max_open_conns and database.max_idle_conns must not be negative")
 	}
+	if c.MaxOpenConns > 0 && c.MaxIdleConns > c.MaxOpenConns {
+		errs = append(errs, fmt.Sprintf("database.max_idle_conns (%d) must not exceed database.max_open_conns (%d)", c.MaxIdleConns, c.MaxOpenConns))
+	}
+	if c.ConnMaxLifetime < 0 || c.ConnMaxIdleTime < 0 {
+		errs = append(errs, "database.conn_max_lifetime and database.conn_max_idle_time must not be negative")
+	}
+	return errs
+}
 
-	host := os.Getenv("DB_HOST")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbName := os.Getenv("DB_NAME")
-	port := os.Getenv("DB_PORT")
+// ConnectDatabase membuka koneksi sesuai cfg.Database, mengatur pool koneksi dan mengisi DB
+func ConnectDatabase(cfg *Config) error {
+	c := cfg.Database
 
-	// Format DSN (Data Source Name) untuk PostgreSQL
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable TimeZone=Asia/Shanghai", host, user, password, dbName, port)
+	var dialector gorm.Dialector
+	switch c.Driver {
+	case DriverPostgres:
+		// Format DSN (Data Source Name) untuk PostgreSQL
+		dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s TimeZone=%s", c.Host, c.User, c.Password, c.Name, c.Port, c.SSLMode, c.TimeZone)
+		dialector = postgres.Open(dsn)
+	case DriverSQLite:
+		dialector = sqlite.New(sqlite.Config{DriverName: sqliteDriverName, DSN: sqliteDSN(c.Path)})
+	default:
+		return fmt.Errorf("unsupported database driver %q, expected %s or %s", c.Driver, DriverPostgres, DriverSQLite)
+	}
+
+	database, err := gorm.Open(dialector, &gorm.Config{
+		// Pelanggaran unique dikembalikan sebagai gorm.ErrDuplicatedKey, sama dengan MemoryRepository
+		TranslateError: true,
+		// Query lambat dan error ditulis lewat slog sebagai JSON, dengan request ID jika query memakai WithContext
+		Logger: logger.NewSlogLogger(slog.Default(), logger.Config{
+			LogLevel:                  gormLogLevel(cfg.Log.Level),
+			SlowThreshold:             200 * time.Millisecond,
+			IgnoreRecordNotFoundError: true,
+		}),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %v", err)
+	}
 
-	database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	sqlDB, err := database.DB()
 	if err != nil {
-		panic("Failed to connect to database")
+		return fmt.Errorf("failed to configure connection pool: %v", err)
 	}
+	sqlDB.SetMaxOpenConns(c.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(c.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(c.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(c.ConnMaxIdleTime)
 
 	DB = database
+	return nil
+}
+
+// gormLogLevel: debug menulis semua query, info dan warn hanya query lambat dan error
+func gormLogLevel(level string) logger.LogLevel {
+	switch level {
+	case LogLevelDebug:
+		return logger.Info
+	case LogLevelError:
+		return logger.Error
+	default:
+		return logger.Warn
+	}
+}
+
+// CloseDatabase menutup pool koneksi DB, dipanggil saat aplikasi berhenti
+func CloseDatabase() error {
+	if DB == nil {
+		return nil
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// EDIPartner adalah pengaturan EDI untuk satu shipping line
+type EDIPartner struct {
+	LineOperator string `json:"line_operator"`
+	Sender       string `json:"sender"`
+	Recipient    string `json:"recipient"`
+}
+
+type EDIConfig struct {
+	// Direktori tempat message keluar ditulis, satu subdirektori per shipping line
+	OutboxDir string `yaml:"outbox_dir"`
+	// File JSON berisi daftar partner, kosong berarti tidak ada partner
+	PartnersFile string `yaml:"partners_file"`
+	// Key: kode shipping line, diisi dari PartnersFile saat validasi
+	Partners map[string]EDIPartner `yaml:"-"`
+}
+
+func (c *EDIConfig) loadEnv(e *envReader) {
+	e.string("EDI_OUTBOX_DIR", &c.OutboxDir)
+	e.string("EDI_PARTNERS_FILE", &c.PartnersFile)
+}
+
+// loadPartners membaca daftar partner dari PartnersFile
+func (c *EDIConfig) loadPartners() []string {
+	c.Partners = make(map[string]EDIPartner)
+	if c.PartnersFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(c.PartnersFile)
+	if err != nil {
+		return []string{fmt.Sprintf("edi.partners_file cannot be read: %v", err)}
+	}
+	var partners []EDIPartner
+	if err := json.Unmarshal(data, &partners); err != nil {
+		return []string{fmt.Sprintf("edi.partners_file %s is not valid JSON: %v", c.PartnersFile, err)}
+	}
+	for _, p := range partners {
+		c.Partners[p.LineOperator] = p
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package config
+
+// FeatureConfig berisi toggle untuk aturan penempatan dan pickup
+type FeatureConfig struct {
+	// Jika aktif (default), /placement dan /pickup membuat job untuk alat di block dan posisi baru disimpan setelah
+	// operator mengkonfirmasi job selesai. Nonaktif berarti /placement dan /pickup langsung menyimpan posisi.
+	WorkQueue bool `yaml:"work_queue"`
+	// Jika aktif, posisi yang dipesan pre-advice dianggap terisi dan penempatan harus sesuai posisi yang dipesan
+	PreAdviceReservation bool `yaml:"pre_advice_reservation"`
+	// Jika aktif, pickup ditolak selama kontainer masih punya hold aktif
+	HoldCheck bool `yaml:"hold_check"`
+}
+
+func (c *FeatureConfig) loadEnv(e *envReader) {
+	e.bool("WORK_QUEUE_ENABLED", &c.WorkQueue)
+	e.bool("PRE_ADVICE_RESERVATION_ENABLED", &c.PreAdviceReservation)
+	e.bool("HOLD_CHECK_ENABLED", &c.HoldCheck)
+}
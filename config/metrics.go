@@ -0,0 +1,23 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+type MetricsConfig struct {
+	// Interval penghitungan ulang gauge occupancy block di latar belakang. Perubahan dari penempatan, pickup dan
+	// shift terlihat di /metrics paling lambat satu interval kemudian.
+	OccupancyInterval time.Duration `yaml:"occupancy_interval"`
+}
+
+func (c *MetricsConfig) loadEnv(e *envReader) {
+	e.duration("METRICS_OCCUPANCY_INTERVAL", &c.OccupancyInterval)
+}
+
+func (c MetricsConfig) validate() []string {
+	if c.OccupancyInterval <= 0 {
+		return []string{fmt.Sprintf("metrics.occupancy_interval must be positive, got %s", c.OccupancyInterval)}
+	}
+	return nil
+}
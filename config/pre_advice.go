@@ -0,0 +1,22 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+type PreAdviceConfig struct {
+	// Lama posisi tetap dipesan setelah jendela kedatangan lewat dan truk belum tiba
+	Grace time.Duration `yaml:"grace"`
+}
+
+func (c *PreAdviceConfig) loadEnv(e *envReader) {
+	e.duration("PRE_ADVICE_GRACE", &c.Grace)
+}
+
+func (c PreAdviceConfig) validate() []string {
+	if c.Grace < 0 {
+		return []string{fmt.Sprintf("pre_advice.grace must not be negative, got %s", c.Grace)}
+	}
+	return nil
+}
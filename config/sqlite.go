@@ -0,0 +1,24 @@
+package config
+
+import "strings"
+
+// sqliteDriverName adalah driver go-sqlite3 yang menyimpan semua waktu dalam UTC, didaftarkan di sqlite_cgo.go.
+// SQLite menyimpan waktu sebagai teks dan membandingkannya per karakter, jadi waktu dengan zona berbeda (time.Now()
+// lokal vs tanggal filter UTC) akan salah urut jika tidak diseragamkan.
+const sqliteDriverName = "sqlite3_utc"
+
+// sqliteDSN menyusun DSN dari database.path. Kosong berarti file yard.db, ":memory:" berarti database di memori yang
+// dipakai bersama semua koneksi pool. Waktu dibaca kembali dalam zona lokal, dan file database memakai WAL supaya
+// pembacaan tidak memblokir penulisan dari koneksi lain.
+func sqliteDSN(path string) string {
+	switch path {
+	case "":
+		path = "yard.db"
+	case ":memory:":
+		return "file::memory:?cache=shared&_loc=auto&_fk=1"
+	}
+	if strings.Contains(path, "?") {
+		return path
+	}
+	return "file:" + path + "?_loc=auto&_fk=1&_journal_mode=WAL&_busy_timeout=5000"
+}
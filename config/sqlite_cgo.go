@@ -0,0 +1,49 @@
+//go:build cgo
+
+package config
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// sqliteSupported bernilai true jika binary dibangun dengan cgo, yang dibutuhkan github.com/mattn/go-sqlite3
+const sqliteSupported = true
+
+func init() {
+	sql.Register(sqliteDriverName, &utcSQLiteDriver{})
+}
+
+type utcSQLiteDriver struct {
+	sqlite3.SQLiteDriver
+}
+
+func (d *utcSQLiteDriver) Open(dsn string) (driver.Conn, error) {
+	conn, err := d.SQLiteDriver.Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &utcSQLiteConn{conn.(*sqlite3.SQLiteConn)}, nil
+}
+
+type utcSQLiteConn struct {
+	*sqlite3.SQLiteConn
+}
+
+// CheckNamedValue mengubah parameter waktu ke UTC, parameter lain memakai konversi bawaan database/sql
+func (c *utcSQLiteConn) CheckNamedValue(nv *driver.NamedValue) error {
+	switch v := nv.Value.(type) {
+	case time.Time:
+		nv.Value = v.UTC()
+		return nil
+	case *time.Time:
+		if v != nil {
+			nv.Value = v.UTC()
+			return nil
+		}
+	}
+	return driver.ErrSkip
+}
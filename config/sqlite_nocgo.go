@@ -0,0 +1,7 @@
+//go:build !cgo
+
+package config
+
+// sqliteSupported bernilai false untuk binary CGO_ENABLED=0. go-sqlite3 tetap terkompilasi sebagai stub yang
+// selalu gagal saat koneksi dibuka, sehingga driver sqlite ditolak lebih awal saat validasi konfigurasi.
+const sqliteSupported = false
@@ -0,0 +1,162 @@
+package edifact
+
+import (
+	"fmt"
+	"time"
+)
+
+// Kode dokumen BGM pada CODECO
+const (
+	CodecoGateIn  = "34"
+	CodecoGateOut = "36"
+)
+
+// Kode full/empty pada element terakhir EQD
+const (
+	EquipmentFull  = "5"
+	EquipmentEmpty = "4"
+)
+
+type CodecoEvent struct {
+	MessageRef      string
+	GateIn          bool // true = gate-in (34), false = gate-out (36)
+	ContainerNumber string
+	SizeType        string
+	Full            bool // Status EQD: 5 = full, 4 = empty
+	EventTime       time.Time
+	Segment         int // Index segment EQD di interchange, untuk laporan error
+}
+
+// SegmentError adalah error yang terjadi pada satu segment tertentu
+type SegmentError struct {
+	MessageRef      string `json:"message_ref"`
+	Segment         int    `json:"segment"`
+	Tag             string `json:"tag"`
+	ContainerNumber string `json:"container_number,omitempty"`
+	Message         string `json:"message"`
+}
+
+// ParseCodeco mengambil event kontainer (satu per grup EQD) dari sebuah message CODECO
+func ParseCodeco(msg Message) ([]CodecoEvent, []SegmentError) {
+	var events []CodecoEvent
+	var errs []SegmentError
+
+	if msg.Type != "CODECO" {
+		return nil, []SegmentError{{MessageRef: msg.Ref, Segment: msg.Segments[0].Index, Tag: "UNH", Message: fmt.Sprintf("unsupported message type %s", msg.Type)}}
+	}
+
+	gateIn := false
+	hasBGM := false
+	var current *CodecoEvent
+	for _, seg := range msg.Segments {
+		switch seg.Tag {
+		case "BGM":
+			switch seg.Value(0, 0) {
+			case CodecoGateIn:
+				gateIn = true
+			case CodecoGateOut:
+				gateIn = false
+			default:
+				errs = append(errs, SegmentError{MessageRef: msg.Ref, Segment: seg.Index, Tag: seg.Tag, Message: fmt.Sprintf("unsupported document code %s, expected 34 (gate-in) or 36 (gate-out)", seg.Value(0, 0))})
+				return nil, errs
+			}
+			hasBGM = true
+		case "EQD":
+			if current != nil {
+				events = append(events, *current)
+			}
+			current = nil
+			if seg.Value(0, 0) != "CN" {
+				continue
+			}
+			if !hasBGM {
+				errs = append(errs, SegmentError{MessageRef: msg.Ref, Segment: seg.Index, Tag: seg.Tag, Message: "EQD before BGM"})
+				return nil, errs
+			}
+			current = &CodecoEvent{
+				MessageRef:      msg.Ref,
+				GateIn:          gateIn,
+				ContainerNumber: seg.Value(1, 0),
+				SizeType:        seg.Value(2, 0),
+				Full:            seg.Value(5, 0) == EquipmentFull,
+				Segment:         seg.Index,
+			}
+			if current.ContainerNumber == "" {
+				errs = append(errs, SegmentError{MessageRef: msg.Ref, Segment: seg.Index, Tag: seg.Tag, Message: "missing container number"})
+				current = nil
+			}
+		case "DTM":
+			if current == nil || seg.Value(0, 0) != "7" {
+				continue
+			}
+			t, err := ParseDateTime(seg.Value(0, 1), seg.Value(0, 2))
+			if err != nil {
+				errs = append(errs, SegmentError{MessageRef: msg.Ref, Segment: seg.Index, Tag: seg.Tag, ContainerNumber: current.ContainerNumber, Message: err.Error()})
+				continue
+			}
+			current.EventTime = t
+		case "CNT", "UNT":
+			if current != nil {
+				events = append(events, *current)
+				current = nil
+			}
+		}
+	}
+	return events, errs
+}
+
+// ParseDateTime membaca nilai DTM dengan format qualifier 102 (CCYYMMDD), 203 (CCYYMMDDHHMM) atau 204 (CCYYMMDDHHMMSS)
+func ParseDateTime(value, format string) (time.Time, error) {
+	layouts := map[string]string{"102": "20060102", "203": "200601021504", "204": "20060102150405"}
+	layout, ok := layouts[format]
+	if !ok {
+		return time.Time{}, fmt.Errorf("unsupported date format qualifier %s", format)
+	}
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %s for format %s", value, format)
+	}
+	return t, nil
+}
+
+type CodecoParams struct {
+	Sender          string
+	Recipient       string
+	Reference       string // Dipakai sebagai interchange control reference dan message reference
+	GateIn          bool
+	ContainerNumber string
+	SizeType        string
+	Full            bool   // false = kontainer kosong
+	Location        string // Kode yard/terminal
+	EventTime       time.Time
+}
+
+// BuildCodeco membuat interchange CODECO berisi satu message untuk satu kontainer
+func BuildCodeco(p CodecoParams) string {
+	docCode := CodecoGateOut
+	if p.GateIn {
+		docCode = CodecoGateIn
+	}
+	fullEmpty := EquipmentEmpty
+	if p.Full {
+		fullEmpty = EquipmentFull
+	}
+	now := p.EventTime
+
+	segments := []Segment{
+		NewSegment("UNB", E("UNOA", "2"), E(p.Sender), E(p.Recipient), E(now.Format("060102"), now.Format("1504")), E(p.Reference)),
+		NewSegment("UNH", E(p.Reference), E("CODECO", "D", "95B", "UN", "ITG14")),
+		NewSegment("BGM", E(docCode), E(p.Reference), E("9")),
+		NewSegment("NAD", E("MS"), E(p.Sender)),
+		NewSegment("EQD", E("CN"), E(p.ContainerNumber), E(p.SizeType, "102", "5"), E(), E(), E(fullEmpty)),
+		NewSegment("DTM", E("7", now.Format("200601021504"), "203")),
+		NewSegment("LOC", E("165"), E(p.Location, "TER", "ZZZ")),
+		NewSegment("CNT", E("16", "1")),
+	}
+	// UNT menghitung segment dari UNH sampai UNT, len(segments) termasuk UNB sehingga sama dengan UNH..CNT ditambah UNT
+	segments = append(segments,
+		NewSegment("UNT", E(fmt.Sprintf("%d", len(segments))), E(p.Reference)),
+		NewSegment("UNZ", E("1"), E(p.Reference)),
+	)
+	return Format(segments)
+}
@@ -0,0 +1,147 @@
+package edifact
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Delimiters adalah karakter pemisah EDIFACT, default sesuai UNA:+.? '
+type Delimiters struct {
+	Component byte
+	Element   byte
+	Decimal   byte
+	Release   byte
+	Segment   byte
+}
+
+var DefaultDelimiters = Delimiters{Component: ':', Element: '+', Decimal: '.', Release: '?', Segment: '\''}
+
+type Segment struct {
+	Tag      string
+	Elements [][]string // Elements[i][j] = komponen j dari data element i (tanpa tag)
+	Index    int        // Posisi segment di interchange, dimulai dari 1
+}
+
+// Value mengambil komponen, kosong jika tidak ada
+func (s Segment) Value(element, component int) string {
+	if element >= len(s.Elements) || component >= len(s.Elements[element]) {
+		return ""
+	}
+	return s.Elements[element][component]
+}
+
+type Message struct {
+	Ref      string // UNH reference
+	Type     string // Contoh: CODECO, COARRI, BAPLIE
+	Segments []Segment
+}
+
+type Interchange struct {
+	Sender     string
+	Recipient  string
+	ControlRef string
+	Messages   []Message
+}
+
+// Parse memecah data EDIFACT menjadi segment, UNA (jika ada) menentukan delimiter
+func Parse(data string) ([]Segment, error) {
+	d := DefaultDelimiters
+	data = strings.TrimLeft(data, " \r\n\t")
+	if strings.HasPrefix(data, "UNA") {
+		if len(data) < 9 {
+			return nil, fmt.Errorf("invalid UNA service string advice")
+		}
+		d = Delimiters{Component: data[3], Element: data[4], Decimal: data[5], Release: data[6], Segment: data[8]}
+		data = data[9:]
+	}
+
+	var segments []Segment
+	var elements [][]string
+	var component strings.Builder
+	var components []string
+	released := false
+
+	flushComponent := func() {
+		components = append(components, component.String())
+		component.Reset()
+	}
+	flushElement := func() {
+		flushComponent()
+		elements = append(elements, components)
+		components = nil
+	}
+
+	for i := 0; i < len(data); i++ {
+		ch := data[i]
+		if released {
+			component.WriteByte(ch)
+			released = false
+			continue
+		}
+		switch ch {
+		case d.Release:
+			released = true
+		case d.Component:
+			flushComponent()
+		case d.Element:
+			flushElement()
+		case d.Segment:
+			flushElement()
+			tag := strings.TrimSpace(elements[0][0])
+			if tag != "" {
+				segments = append(segments, Segment{Tag: tag, Elements: elements[1:], Index: len(segments) + 1})
+			}
+			elements = nil
+		case '\r', '\n':
+			// Baris baru di antara segment diabaikan
+		default:
+			component.WriteByte(ch)
+		}
+	}
+	if rest := strings.TrimSpace(component.String()); rest != "" || len(elements) > 0 {
+		return nil, fmt.Errorf("unterminated segment at end of data")
+	}
+	return segments, nil
+}
+
+// ParseInterchange memecah data menjadi interchange (UNB/UNZ) dengan message (UNH/UNT)
+func ParseInterchange(data string) (*Interchange, error) {
+	segments, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	interchange := &Interchange{}
+	var current *Message
+	for _, seg := range segments {
+		switch seg.Tag {
+		case "UNB":
+			interchange.Sender = seg.Value(1, 0)
+			interchange.Recipient = seg.Value(2, 0)
+			interchange.ControlRef = seg.Value(4, 0)
+		case "UNH":
+			if current != nil {
+				return nil, fmt.Errorf("segment %d: UNH %s started before UNT of message %s", seg.Index, seg.Value(0, 0), current.Ref)
+			}
+			current = &Message{Ref: seg.Value(0, 0), Type: seg.Value(1, 0)}
+			current.Segments = append(current.Segments, seg)
+		case "UNT":
+			if current == nil {
+				return nil, fmt.Errorf("segment %d: UNT without UNH", seg.Index)
+			}
+			current.Segments = append(current.Segments, seg)
+			interchange.Messages = append(interchange.Messages, *current)
+			current = nil
+		case "UNZ":
+		default:
+			if current == nil {
+				return nil, fmt.Errorf("segment %d: %s outside of message", seg.Index, seg.Tag)
+			}
+			current.Segments = append(current.Segments, seg)
+		}
+	}
+	if current != nil {
+		return nil, fmt.Errorf("message %s has no UNT", current.Ref)
+	}
+	return interchange, nil
+}
@@ -0,0 +1,78 @@
+package edifact
+
+import "fmt"
+
+// SizeType adalah hasil decode kode ISO 6346 size-type (contoh: 22G1, 45R1)
+type SizeType struct {
+	Code   string
+	Size   int     // 20 atau 40
+	Height float64 // 8.6 atau 9.6
+	Type   string  // DRY, REEFER, OT, dll
+}
+
+var isoHeights = map[byte]float64{'0': 8.0, '2': 8.6, '4': 9.0, '5': 9.6}
+
+var isoTypes = map[byte]string{'G': "DRY", 'V': "DRY", 'R': "REEFER", 'H': "REEFER", 'U': "OT", 'P': "FR", 'T': "TANK", 'B': "BULK", 'S': "DRY"}
+
+func ParseSizeType(code string) (*SizeType, error) {
+	if len(code) != 4 {
+		return nil, fmt.Errorf("invalid ISO size-type code %q", code)
+	}
+
+	st := &SizeType{Code: code}
+	switch code[0] {
+	case '2':
+		st.Size = 20
+	case '4':
+		st.Size = 40
+	default:
+		return nil, fmt.Errorf("unsupported container length in ISO size-type code %q", code)
+	}
+
+	height, ok := isoHeights[code[1]]
+	if !ok {
+		return nil, fmt.Errorf("unsupported container height in ISO size-type code %q", code)
+	}
+	st.Height = height
+
+	ctype, ok := isoTypes[code[2]]
+	if !ok {
+		return nil, fmt.Errorf("unsupported container type in ISO size-type code %q", code)
+	}
+	st.Type = ctype
+
+	return st, nil
+}
+
+// FormatSizeType membuat kode ISO 6346 size-type dari ukuran, tinggi dan tipe, kebalikan dari ParseSizeType
+func FormatSizeType(size int, height float64, ctype string) (string, error) {
+	var code [4]byte
+	switch size {
+	case 20:
+		code[0] = '2'
+	case 40:
+		code[0] = '4'
+	default:
+		return "", fmt.Errorf("unsupported container size %d", size)
+	}
+
+	for c, h := range isoHeights {
+		if h == height {
+			code[1] = c
+		}
+	}
+	if code[1] == 0 {
+		return "", fmt.Errorf("unsupported container height %.1f", height)
+	}
+
+	// Kode tipe utama untuk setiap tipe, urutan isoTypes tidak bisa dipakai karena satu tipe punya beberapa kode
+	typeCodes := map[string]byte{"DRY": 'G', "REEFER": 'R', "OT": 'U', "FR": 'P', "TANK": 'T', "BULK": 'B'}
+	t, ok := typeCodes[ctype]
+	if !ok {
+		return "", fmt.Errorf("unsupported container type %s", ctype)
+	}
+	code[2] = t
+	code[3] = '1'
+
+	return string(code[:]), nil
+}
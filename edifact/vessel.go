@@ -0,0 +1,112 @@
+package edifact
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// VesselContainer adalah satu kontainer dari BAPLIE (bay plan) atau COARRI (discharge/load report)
+type VesselContainer struct {
+	MessageRef      string
+	ContainerNumber string
+	SizeType        string
+	WeightKg        float64
+	POL             string // Port of loading (LOC+9)
+	POD             string // Port of discharge (LOC+11)
+	Stowage         string // Posisi di kapal (LOC+147), format bay-row-tier
+	Full            bool
+	Segment         int // Index segment awal grup, untuk laporan error
+}
+
+type VesselInfo struct {
+	Voyage     string
+	VesselID   string
+	VesselName string
+}
+
+// ParseVesselMessage membaca BAPLIE atau COARRI. Di BAPLIE satu grup kontainer dimulai dengan LOC+147,
+// di COARRI dimulai dengan EQD.
+func ParseVesselMessage(msg Message) (*VesselInfo, []VesselContainer, []SegmentError) {
+	var groupStart string
+	switch msg.Type {
+	case "BAPLIE":
+		groupStart = "LOC+147"
+	case "COARRI":
+		groupStart = "EQD"
+	default:
+		return nil, nil, []SegmentError{{MessageRef: msg.Ref, Segment: msg.Segments[0].Index, Tag: "UNH", Message: fmt.Sprintf("unsupported message type %s, expected BAPLIE or COARRI", msg.Type)}}
+	}
+
+	info := &VesselInfo{}
+	var containers []VesselContainer
+	var errs []SegmentError
+	var current *VesselContainer
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		if current.ContainerNumber == "" {
+			// Cell kosong di BAPLIE atau EQD tanpa nomor tidak dilaporkan sebagai kontainer
+			if msg.Type == "COARRI" {
+				errs = append(errs, SegmentError{MessageRef: msg.Ref, Segment: current.Segment, Tag: "EQD", Message: "missing container number"})
+			}
+		} else {
+			containers = append(containers, *current)
+		}
+		current = nil
+	}
+
+	for _, seg := range msg.Segments {
+		key := seg.Tag
+		if seg.Tag == "LOC" {
+			key = "LOC+" + seg.Value(0, 0)
+		}
+		if key == groupStart {
+			flush()
+			current = &VesselContainer{MessageRef: msg.Ref, Segment: seg.Index}
+		}
+
+		switch key {
+		case "TDT":
+			info.Voyage = seg.Value(1, 0)
+			info.VesselID = seg.Value(7, 0)
+			info.VesselName = seg.Value(7, 3)
+		case "UNT":
+			flush()
+		}
+		if current == nil {
+			continue
+		}
+
+		switch key {
+		case "LOC+147":
+			current.Stowage = seg.Value(1, 0)
+		case "LOC+9":
+			current.POL = seg.Value(1, 0)
+		case "LOC+11":
+			current.POD = seg.Value(1, 0)
+		case "MEA":
+			// MEA+WT++KGM:24000 atau MEA+AAE+VGM+KGM:24000
+			if seg.Value(2, 0) != "KGM" {
+				continue
+			}
+			weight, err := strconv.ParseFloat(seg.Value(2, 1), 64)
+			if err != nil {
+				errs = append(errs, SegmentError{MessageRef: msg.Ref, Segment: seg.Index, Tag: seg.Tag, ContainerNumber: current.ContainerNumber, Message: fmt.Sprintf("invalid weight %s", seg.Value(2, 1))})
+				continue
+			}
+			current.WeightKg = weight
+		case "EQD":
+			if seg.Value(0, 0) != "CN" {
+				continue
+			}
+			current.ContainerNumber = seg.Value(1, 0)
+			current.SizeType = seg.Value(2, 0)
+			current.Full = seg.Value(5, 0) == "5"
+		}
+	}
+	flush()
+
+	return info, containers, errs
+}
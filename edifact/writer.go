@@ -0,0 +1,59 @@
+package edifact
+
+import "strings"
+
+// Format menyusun segment menjadi teks EDIFACT dengan delimiter default, satu segment per baris
+func Format(segments []Segment) string {
+	d := DefaultDelimiters
+	var b strings.Builder
+	for _, seg := range segments {
+		b.WriteString(seg.Tag)
+		for _, element := range seg.Elements {
+			b.WriteByte(d.Element)
+			for i, component := range element {
+				if i > 0 {
+					b.WriteByte(d.Component)
+				}
+				b.WriteString(escape(component, d))
+			}
+		}
+		b.WriteByte(d.Segment)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// NewSegment membuat segment dari elements, setiap element berisi satu atau lebih komponen
+func NewSegment(tag string, elements ...[]string) Segment {
+	// Element kosong di akhir tidak perlu ditulis
+	for len(elements) > 0 && isEmptyElement(elements[len(elements)-1]) {
+		elements = elements[:len(elements)-1]
+	}
+	return Segment{Tag: tag, Elements: elements}
+}
+
+// E adalah singkatan untuk satu element dengan komponen-komponennya
+func E(components ...string) []string {
+	return components
+}
+
+func isEmptyElement(element []string) bool {
+	for _, c := range element {
+		if c != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func escape(value string, d Delimiters) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case d.Component, d.Element, d.Release, d.Segment:
+			b.WriteByte(d.Release)
+		}
+		b.WriteByte(value[i])
+	}
+	return b.String()
+}
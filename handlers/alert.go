@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+	"yard-calculation/services"
+	"yard-calculation/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type CapacityAlertHandler struct {
+	Service *services.CapacityAlertService
+}
+
+func NewCapacityAlertHandler(service *services.CapacityAlertService) *CapacityAlertHandler {
+	return &CapacityAlertHandler{Service: service}
+}
+
+func (h *CapacityAlertHandler) CheckPlanCapacity(c *fiber.Ctx) error {
+	threshold := c.QueryFloat("threshold", 0)
+
+	// Validasi input
+	if threshold < 0 || threshold > 100 {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: threshold must be between 0 and 100")
+		return nil
+	}
+
+	report, err := h.Service.CheckPlanCapacity(c.UserContext(), threshold)
+	if err != nil {
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error Check Plan Capacity", nil, err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Check Plan Capacity", report, nil)
+	return nil
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"yard-calculation/schemas"
+	"yard-calculation/services"
+	"yard-calculation/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditHandler struct {
+	Service *services.AuditService
+}
+
+func NewAuditHandler(service *services.AuditService) *AuditHandler {
+	return &AuditHandler{Service: service}
+}
+
+func (h *AuditHandler) ListAuditEntries(c *fiber.Ctx) error {
+	req := new(schemas.ListAuditRequest)
+	if err := c.QueryParser(req); err != nil {
+		utils.ApiResponse(c, http.StatusBadRequest, "Cannot parse query", nil, "Cannot parse query")
+		return nil
+	}
+
+	entries, err := h.Service.ListAuditEntries(c.UserContext(), *req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
+			return nil
+		}
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error List Audit Log", nil, err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "List Audit Log", entries, nil)
+	return nil
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"yard-calculation/models"
+	"yard-calculation/schemas"
+	"yard-calculation/services"
+	"yard-calculation/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type BillingHandler struct {
+	Service *services.BillingService
+}
+
+func NewBillingHandler(service *services.BillingService) *BillingHandler {
+	return &BillingHandler{Service: service}
+}
+
+func (h *BillingHandler) CreateTariff(c *fiber.Ctx) error {
+	req := new(schemas.CreateTariffRequest)
+	if err := c.BodyParser(req); err != nil {
+		utils.ApiResponse(c, http.StatusBadRequest, "Cannot parse JSON", nil, "Cannot parse JSON")
+		return nil
+	}
+
+	// Validasi input
+	if (req.ContainerSize != 0 && req.ContainerSize != 20 && req.ContainerSize != 40) ||
+		(req.CargoStatus != "" && req.CargoStatus != models.CargoStatusFull && req.CargoStatus != models.CargoStatusEmpty) ||
+		req.FreeDays < 0 || req.DailyRate < 0 {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: container_size must be 0, 20 or 40, cargo_status must be FULL or EMPTY, and free_days and daily_rate must not be negative")
+		return nil
+	}
+
+	tariff, err := h.Service.CreateTariff(c.UserContext(), *req)
+	if err != nil {
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error Create Tariff", nil, err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Create Tariff Success", tariff, nil)
+	return nil
+}
+
+func (h *BillingHandler) ListTariffs(c *fiber.Ctx) error {
+	tariffs, err := h.Service.ListTariffs(c.UserContext())
+	if err != nil {
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error List Tariffs", nil, err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "List Tariffs", tariffs, nil)
+	return nil
+}
+
+func (h *BillingHandler) DeleteTariff(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: tariff id must be a positive number")
+		return nil
+	}
+
+	if err := h.Service.DeleteTariff(c.UserContext(), uint(id)); err != nil {
+		if err.Error() == fmt.Sprintf("tariff %d not found", id) {
+			utils.ApiResponse(c, http.StatusNotFound, "Error Delete Tariff", nil, err.Error())
+			return nil
+		}
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error Delete Tariff", nil, err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Delete Tariff Success", nil, nil)
+	return nil
+}
+
+func (h *BillingHandler) GetStorageCharges(c *fiber.Ctx) error {
+	req := new(schemas.StorageChargeRequest)
+	if err := c.QueryParser(req); err != nil {
+		utils.ApiResponse(c, http.StatusBadRequest, "Cannot parse query", nil, "Cannot parse query")
+		return nil
+	}
+
+	// Validasi input
+	if req.Yard == "" || req.From == "" || req.To == "" {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: yard, from, and to are required")
+		return nil
+	}
+
+	result, err := h.Service.GetStorageCharges(c.UserContext(), *req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
+			return nil
+		}
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error Get Storage Charges", nil, err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Get Storage Charges", result, nil)
+	return nil
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"yard-calculation/services"
+	"yard-calculation/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type BlockHandler struct {
+	Service *services.BlockService
+}
+
+func NewBlockHandler(service *services.BlockService) *BlockHandler {
+	return &BlockHandler{Service: service}
+}
+
+func (h *BlockHandler) GetBlockMap(c *fiber.Ctx) error {
+	yardName := c.Params("yard")
+	blockName := c.Params("block")
+	format := c.Query("format", "json")
+
+	// Validasi input
+	if format != "json" && format != "text" {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: format must be json or text")
+		return nil
+	}
+
+	blockMap, err := h.Service.GetBlockMap(c.UserContext(), yardName, blockName)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("block with name %s in yard %s not found", blockName, yardName) {
+			utils.ApiResponse(c, http.StatusNotFound, "Error Get Block Map", nil, err.Error())
+			return nil
+		}
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error Get Block Map", nil, err.Error())
+		return nil
+	}
+
+	if format == "text" {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+		return c.Status(http.StatusOK).SendString(services.RenderBlockMapText(blockMap))
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Get Block Map", blockMap, nil)
+	return nil
+}
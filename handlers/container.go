@@ -3,6 +3,9 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"strings"
+	"yard-calculation/logging"
+	"yard-calculation/models"
 	"yard-calculation/schemas"
 	"yard-calculation/services"
 	"yard-calculation/utils"
@@ -12,6 +15,8 @@ import (
 
 type ContainerHandler struct {
 	Service *services.ContainerService
+	// Queue diisi jika antrian kerja aktif, placement dan pickup lalu dijadikan job untuk alat di block
+	Queue *services.WorkQueueService
 }
 
 func NewContainerHandler(service *services.ContainerService) *ContainerHandler {
@@ -31,8 +36,13 @@ func (h *ContainerHandler) GetSuggestion(c *fiber.Ctx) error {
 		return nil
 	}
 
-	suggestedContainer, err := h.Service.GetSuggestedPosition(req.Yard, req.ContainerNumber, req.ContainerSize, req.ContainerHeight, req.ContainerType)
+	ctx := logContext(c, logging.KeyYard, req.Yard, logging.KeyContainer, req.ContainerNumber)
+	suggestedContainer, err := h.Service.GetSuggestedPosition(ctx, req.Yard, req.ContainerNumber, req.ContainerSize, req.ContainerHeight, req.ContainerType)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), fmt.Sprintf("container %s is reserved at ", req.ContainerNumber)) {
+			utils.ApiResponse(c, http.StatusConflict, "Error Get Suggest", nil, err.Error())
+			return nil
+		}
 		utils.ApiResponse(c, http.StatusInternalServerError, "Error Get Suggest", nil, err.Error())
 		return nil
 	}
@@ -41,6 +51,30 @@ func (h *ContainerHandler) GetSuggestion(c *fiber.Ctx) error {
 	return nil
 }
 
+func (h *ContainerHandler) GetBatchSuggestion(c *fiber.Ctx) error {
+	req := new(schemas.BatchSuggestRequest)
+	if err := c.BodyParser(req); err != nil {
+		utils.ApiResponse(c, http.StatusBadRequest, "Cannot parse JSON", nil, "Cannot parse JSON")
+		return nil
+	}
+
+	// Validasi input, spesifikasi per kontainer divalidasi di service dan dilaporkan per item
+	if req.Yard == "" || len(req.Containers) == 0 {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: yard and at least one container are required")
+		return nil
+	}
+
+	ctx := logContext(c, logging.KeyYard, req.Yard)
+	result, err := h.Service.SuggestBatch(ctx, req.Yard, req.Containers)
+	if err != nil {
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error Get Batch Suggest", nil, err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Suggest Batch Container", result, nil)
+	return nil
+}
+
 func (h *ContainerHandler) PlaceContainer(c *fiber.Ctx) error {
 	req := new(schemas.PlaceContainerRequest)
 	if err := c.BodyParser(req); err != nil {
@@ -53,8 +87,25 @@ func (h *ContainerHandler) PlaceContainer(c *fiber.Ctx) error {
 		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: yard, container_number, and valid size (20 or 40) are required")
 		return nil
 	}
+	// cargo_status kosong diisi dari CODECO gate-in, atau FULL jika tidak ada
+	if req.CargoStatus != "" && req.CargoStatus != models.CargoStatusFull && req.CargoStatus != models.CargoStatusEmpty {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: cargo_status must be FULL or EMPTY")
+		return nil
+	}
+
+	ctx := logContext(c, logging.KeyYard, req.Yard, logging.KeyContainer, req.ContainerNumber, logging.KeyBlock, req.Block)
 
-	err := h.Service.PlaceContainerDetailed(req.Yard, req.ContainerNumber, req.Block, req.Slot, req.Row, req.Tier, req.ContainerSize, req.ContainerHeight, req.ContainerType)
+	if h.Queue != nil {
+		job, err := h.Queue.CreatePlaceJob(ctx, req.Yard, req.ContainerNumber, req.Block, req.Slot, req.Row, req.Tier, req.ContainerSize, req.ContainerHeight, req.ContainerType, req.LineOperator, req.CargoStatus)
+		if err != nil {
+			utils.ApiResponse(c, http.StatusInternalServerError, "Error Place Container", nil, err.Error())
+			return nil
+		}
+		utils.ApiResponse(c, http.StatusOK, "Place Container Job Created", job, nil)
+		return nil
+	}
+
+	err := h.Service.PlaceContainerDetailed(ctx, req.Yard, req.ContainerNumber, req.Block, req.Slot, req.Row, req.Tier, req.ContainerSize, req.ContainerHeight, req.ContainerType, req.LineOperator, req.CargoStatus)
 	if err != nil {
 		utils.ApiResponse(c, http.StatusInternalServerError, "Error Place Container", nil, err.Error())
 		return nil
@@ -77,16 +128,78 @@ func (h *ContainerHandler) PickupContainer(c *fiber.Ctx) error {
 		return nil
 	}
 
-	err := h.Service.PickupContainer(req.Yard, req.ContainerNumber)
-	if err != nil {
-		if err.Error() == fmt.Sprintf("container with number %s not found or not placed", req.ContainerNumber) {
-			utils.ApiResponse(c, http.StatusNotFound, "Error Pickup Container", nil, err.Error())
+	ctx := logContext(c, logging.KeyYard, req.Yard, logging.KeyContainer, req.ContainerNumber)
+	if h.Queue != nil {
+		job, err := h.Queue.CreatePickupJob(ctx, req.Yard, req.ContainerNumber)
+		if err != nil {
+			pickupError(c, req.ContainerNumber, err)
 			return nil
 		}
-		utils.ApiResponse(c, http.StatusInternalServerError, "Error Pickup Container", nil, err.Error())
+		utils.ApiResponse(c, http.StatusOK, "Pickup Container Job Created", job, nil)
+		return nil
+	}
+
+	err := h.Service.PickupContainer(ctx, req.Yard, req.ContainerNumber)
+	if err != nil {
+		pickupError(c, req.ContainerNumber, err)
 		return nil
 	}
 
 	utils.ApiResponse(c, http.StatusOK, "Pickup Container Success", nil, nil)
 	return nil
 }
+
+func pickupError(c *fiber.Ctx, containerNumber string, err error) {
+	switch {
+	case err.Error() == fmt.Sprintf("container with number %s not found or not placed", containerNumber):
+		utils.ApiResponse(c, http.StatusNotFound, "Error Pickup Container", nil, err.Error())
+	case strings.HasPrefix(err.Error(), fmt.Sprintf("container %s has active hold", containerNumber)):
+		utils.ApiResponse(c, http.StatusConflict, "Error Pickup Container", nil, err.Error())
+	default:
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error Pickup Container", nil, err.Error())
+	}
+}
+
+func (h *ContainerHandler) GetContainer(c *fiber.Ctx) error {
+	containerNumber := c.Params("number")
+	if containerNumber == "" {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: container number is required")
+		return nil
+	}
+
+	detail, err := h.Service.GetContainerDetail(c.UserContext(), containerNumber)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("container with number %s not found", containerNumber) {
+			utils.ApiResponse(c, http.StatusNotFound, "Error Get Container", nil, err.Error())
+			return nil
+		}
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error Get Container", nil, err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Get Container", detail, nil)
+	return nil
+}
+
+func (h *ContainerHandler) SearchContainers(c *fiber.Ctx) error {
+	req := new(schemas.SearchContainerRequest)
+	if err := c.QueryParser(req); err != nil {
+		utils.ApiResponse(c, http.StatusBadRequest, "Cannot parse query", nil, "Cannot parse query")
+		return nil
+	}
+
+	// Validasi input
+	if (req.Size != 0 && req.Size != 20 && req.Size != 40) || (req.Status != "" && req.Status != "placed" && req.Status != "picked_up") {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: size must be 20 or 40 and status must be placed or picked_up")
+		return nil
+	}
+
+	result, err := h.Service.SearchContainers(c.UserContext(), *req)
+	if err != nil {
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error Search Container", nil, err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Search Container", result, nil)
+	return nil
+}
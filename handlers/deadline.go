@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+	"yard-calculation/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Deadline membatasi waktu request. Context request (c.UserContext) dibatalkan setelah timeout sehingga query
+// database dan pencarian posisi berhenti, lalu response error diganti 504 supaya client tahu request terlalu lama.
+func Deadline(timeout time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+		defer cancel()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && (err != nil || c.Response().StatusCode() >= http.StatusInternalServerError) {
+			utils.ApiResponse(c, http.StatusGatewayTimeout, "Request Timeout", nil, fmt.Sprintf("request did not complete within %s", timeout))
+			return nil
+		}
+		return err
+	}
+}
+
+// Router mendaftarkan route ke app fiber dengan Deadline dari konfigurasi di depan handler setiap route
+type Router struct {
+	App *fiber.App
+	// Timeout mengembalikan batas waktu route, contoh: config.HTTPConfig.Timeout
+	Timeout func(method, route string) time.Duration
+	// Before dijalankan setelah Deadline dan sebelum handler setiap route, contoh: autentikasi
+	Before []fiber.Handler
+}
+
+func (r Router) Get(path string, handlers ...fiber.Handler) {
+	r.add(fiber.MethodGet, path, handlers)
+}
+
+func (r Router) Post(path string, handlers ...fiber.Handler) {
+	r.add(fiber.MethodPost, path, handlers)
+}
+
+func (r Router) Delete(path string, handlers ...fiber.Handler) {
+	r.add(fiber.MethodDelete, path, handlers)
+}
+
+func (r Router) add(method, path string, handlers []fiber.Handler) {
+	chain := append([]fiber.Handler{Deadline(r.Timeout(method, path))}, r.Before...)
+	chain = append(chain, handlers...)
+	r.App.Add(method, path, chain...)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"yard-calculation/logging"
+	"yard-calculation/services"
+	"yard-calculation/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type EDIHandler struct {
+	CodecoService *services.CodecoService
+	VesselService *services.VesselService
+}
+
+func NewEDIHandler(codecoService *services.CodecoService, vesselService *services.VesselService) *EDIHandler {
+	return &EDIHandler{CodecoService: codecoService, VesselService: vesselService}
+}
+
+// readEDIBody mengambil isi EDI dari field multipart file atau dari raw body
+func readEDIBody(c *fiber.Ctx) (string, error) {
+	if fh, err := c.FormFile("file"); err == nil {
+		f, err := fh.Open()
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+	return string(c.Body()), nil
+}
+
+func (h *EDIHandler) IngestCodeco(c *fiber.Ctx) error {
+	yardName := c.Query("yard")
+	data, err := readEDIBody(c)
+	if err != nil {
+		utils.ApiResponse(c, http.StatusBadRequest, "Cannot read file", nil, err.Error())
+		return nil
+	}
+
+	// Validasi input
+	if yardName == "" || data == "" {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: yard and CODECO message are required")
+		return nil
+	}
+
+	result, err := h.CodecoService.IngestCodeco(logContext(c, logging.KeyYard, yardName), yardName, data)
+	if err != nil {
+		utils.ApiResponse(c, http.StatusBadRequest, "Error Ingest CODECO", nil, err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Ingest CODECO", result, nil)
+	return nil
+}
+
+func (h *EDIHandler) PlanVesselDischarge(c *fiber.Ctx) error {
+	yardName := c.Query("yard")
+	port := c.Query("port")
+	data, err := readEDIBody(c)
+	if err != nil {
+		utils.ApiResponse(c, http.StatusBadRequest, "Cannot read file", nil, err.Error())
+		return nil
+	}
+
+	// Validasi input
+	if yardName == "" || port == "" || data == "" {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: yard, port and BAPLIE/COARRI message are required")
+		return nil
+	}
+
+	result, err := h.VesselService.PlanDischarge(logContext(c, logging.KeyYard, yardName), yardName, port, c.QueryBool("include_no_pod", false), data)
+	if err != nil {
+		utils.ApiResponse(c, http.StatusBadRequest, "Error Plan Vessel Discharge", nil, err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Plan Vessel Discharge", result, nil)
+	return nil
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+	"yard-calculation/schemas"
+	"yard-calculation/services"
+	"yard-calculation/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var exportContentTypes = map[string]string{
+	"csv":   "text/csv",
+	"jsonl": "application/x-ndjson",
+	"xlsx":  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+type ExportHandler struct {
+	Service *services.ExportService
+}
+
+func NewExportHandler(service *services.ExportService) *ExportHandler {
+	return &ExportHandler{Service: service}
+}
+
+func (h *ExportHandler) ExportInventory(c *fiber.Ctx) error {
+	req := new(schemas.ExportInventoryRequest)
+	if err := c.QueryParser(req); err != nil {
+		utils.ApiResponse(c, http.StatusBadRequest, "Cannot parse query", nil, "Cannot parse query")
+		return nil
+	}
+	if req.Format == "" {
+		req.Format = "csv"
+	}
+
+	// Validasi input
+	contentType, ok := exportContentTypes[req.Format]
+	if !ok {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: format must be csv, jsonl or xlsx")
+		return nil
+	}
+	filter, err := services.InventoryFilter(*req)
+	if err != nil {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
+		return nil
+	}
+
+	filename := fmt.Sprintf("inventory-%s.%s", time.Now().Format("20060102-150405"), req.Format)
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+	// Stream ditulis setelah handler selesai, saat context request sudah dibatalkan. Field log dan batas waktu
+	// request dibawa ke context baru supaya export tetap berhenti setelah deadline.
+	ctx := context.WithoutCancel(c.UserContext())
+	cancel := context.CancelFunc(func() {})
+	if deadline, ok := c.UserContext().Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	}
+
+	// Response dikirim bertahap, status sudah 200 sehingga error di tengah jalan hanya bisa dicatat di log
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+		if err := h.Service.ExportInventory(ctx, w, req.Format, filter); err != nil {
+			slog.ErrorContext(ctx, "error exporting inventory", "error", err)
+		}
+		w.Flush()
+	})
+	return nil
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+	"yard-calculation/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// readyTimeout adalah batas waktu ping database saat pengecekan readiness
+const readyTimeout = 2 * time.Second
+
+// HealthHandler melayani probe Kubernetes. /healthz hanya menandakan proses hidup, /readyz juga mengecek koneksi
+// database dan mengembalikan 503 setelah shutdown dimulai supaya tidak ada request baru yang diarahkan ke sini.
+type HealthHandler struct {
+	DB           *gorm.DB
+	shuttingDown atomic.Bool
+}
+
+func NewHealthHandler(db *gorm.DB) *HealthHandler {
+	return &HealthHandler{DB: db}
+}
+
+// SetShuttingDown menandai aplikasi sedang berhenti, /readyz mulai mengembalikan 503
+func (h *HealthHandler) SetShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+func (h *HealthHandler) Healthz(c *fiber.Ctx) error {
+	utils.ApiResponse(c, http.StatusOK, "OK", nil, nil)
+	return nil
+}
+
+func (h *HealthHandler) Readyz(c *fiber.Ctx) error {
+	if h.shuttingDown.Load() {
+		utils.ApiResponse(c, http.StatusServiceUnavailable, "Not Ready", nil, "server is shutting down")
+		return nil
+	}
+
+	sqlDB, err := h.DB.DB()
+	if err != nil {
+		utils.ApiResponse(c, http.StatusServiceUnavailable, "Not Ready", nil, err.Error())
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), readyTimeout)
+	defer cancel()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		utils.ApiResponse(c, http.StatusServiceUnavailable, "Not Ready", nil, "database is not reachable: "+err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Ready", nil, nil)
+	return nil
+}
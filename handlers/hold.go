@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"yard-calculation/auth"
+	"yard-calculation/models"
+	"yard-calculation/schemas"
+	"yard-calculation/services"
+	"yard-calculation/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HoldHandler struct {
+	Service *services.HoldService
+}
+
+func NewHoldHandler(service *services.HoldService) *HoldHandler {
+	return &HoldHandler{Service: service}
+}
+
+func (h *HoldHandler) AddHold(c *fiber.Ctx) error {
+	req := new(schemas.CreateHoldRequest)
+	if err := c.BodyParser(req); err != nil {
+		utils.ApiResponse(c, http.StatusBadRequest, "Cannot parse JSON", nil, "Cannot parse JSON")
+		return nil
+	}
+	// Identitas yang terautentikasi menggantikan created_by dari body
+	if p := auth.FromContext(c.UserContext()); p != nil {
+		req.CreatedBy = p.String()
+	}
+
+	// Validasi input
+	if req.ContainerNumber == "" || req.Reason == "" || req.CreatedBy == "" || !validHoldType(req.HoldType) {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: container_number, reason, created_by, and hold_type (CUSTOMS, LINE, INSPECTION or UNPAID) are required")
+		return nil
+	}
+
+	hold, err := h.Service.AddHold(c.UserContext(), *req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), fmt.Sprintf("container %s already has active", req.ContainerNumber)) {
+			utils.ApiResponse(c, http.StatusConflict, "Error Add Hold", nil, err.Error())
+			return nil
+		}
+		if strings.Contains(err.Error(), " is not allowed to access yard ") {
+			utils.ApiResponse(c, http.StatusForbidden, "Error Add Hold", nil, err.Error())
+			return nil
+		}
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error Add Hold", nil, err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Add Hold Success", hold, nil)
+	return nil
+}
+
+func (h *HoldHandler) ReleaseHold(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: hold id must be a positive number")
+		return nil
+	}
+
+	req := new(schemas.ReleaseHoldRequest)
+	if err := c.BodyParser(req); err != nil {
+		utils.ApiResponse(c, http.StatusBadRequest, "Cannot parse JSON", nil, "Cannot parse JSON")
+		return nil
+	}
+	// Identitas yang terautentikasi menggantikan released_by dari body
+	if p := auth.FromContext(c.UserContext()); p != nil {
+		req.ReleasedBy = p.String()
+	}
+	if req.ReleasedBy == "" {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: released_by is required")
+		return nil
+	}
+
+	hold, err := h.Service.ReleaseHold(c.UserContext(), uint(id), *req)
+	if err != nil {
+		switch {
+		case err.Error() == fmt.Sprintf("hold %d not found", id):
+			utils.ApiResponse(c, http.StatusNotFound, "Error Release Hold", nil, err.Error())
+		case err.Error() == fmt.Sprintf("hold %d is already released", id):
+			utils.ApiResponse(c, http.StatusConflict, "Error Release Hold", nil, err.Error())
+		case strings.Contains(err.Error(), " is not allowed to access yard "):
+			utils.ApiResponse(c, http.StatusForbidden, "Error Release Hold", nil, err.Error())
+		default:
+			utils.ApiResponse(c, http.StatusInternalServerError, "Error Release Hold", nil, err.Error())
+		}
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Release Hold Success", hold, nil)
+	return nil
+}
+
+func (h *HoldHandler) ListHolds(c *fiber.Ctx) error {
+	req := new(schemas.ListHoldRequest)
+	if err := c.QueryParser(req); err != nil {
+		utils.ApiResponse(c, http.StatusBadRequest, "Cannot parse query", nil, "Cannot parse query")
+		return nil
+	}
+
+	// Validasi input
+	if (req.HoldType != "" && !validHoldType(req.HoldType)) || (req.Status != "" && req.Status != "active" && req.Status != "released") {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: type must be CUSTOMS, LINE, INSPECTION or UNPAID and status must be active or released")
+		return nil
+	}
+
+	holds, err := h.Service.ListHolds(c.UserContext(), *req)
+	if err != nil {
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error List Holds", nil, err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "List Holds", holds, nil)
+	return nil
+}
+
+func validHoldType(holdType string) bool {
+	switch holdType {
+	case models.HoldTypeCustoms, models.HoldTypeLine, models.HoldTypeInspection, models.HoldTypeUnpaid:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"yard-calculation/schemas"
+	"yard-calculation/services"
+	"yard-calculation/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type HousekeepingHandler struct {
+	Service *services.HousekeepingService
+}
+
+func NewHousekeepingHandler(service *services.HousekeepingService) *HousekeepingHandler {
+	return &HousekeepingHandler{Service: service}
+}
+
+func (h *HousekeepingHandler) PlanHousekeeping(c *fiber.Ctx) error {
+	yardName := c.Params("yard")
+	blockName := c.Params("block")
+
+	req := new(schemas.HousekeepingRequest)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(req); err != nil {
+			utils.ApiResponse(c, http.StatusBadRequest, "Cannot parse JSON", nil, "Cannot parse JSON")
+			return nil
+		}
+	}
+
+	// Validasi input
+	if req.MaxMoves < 0 || (req.GroupBy != "" && req.GroupBy != "spec" && req.GroupBy != "line_operator") {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: max_moves must not be negative and group_by must be spec or line_operator")
+		return nil
+	}
+
+	result, err := h.Service.PlanHousekeeping(c.UserContext(), yardName, blockName, *req)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("block with name %s in yard %s not found", blockName, yardName) {
+			utils.ApiResponse(c, http.StatusNotFound, "Error Plan Housekeeping", nil, err.Error())
+			return nil
+		}
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error Plan Housekeeping", nil, err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Plan Housekeeping", result, nil)
+	return nil
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+	"yard-calculation/services"
+	"yard-calculation/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type LayoutImportHandler struct {
+	Service *services.LayoutImportService
+}
+
+func NewLayoutImportHandler(service *services.LayoutImportService) *LayoutImportHandler {
+	return &LayoutImportHandler{Service: service}
+}
+
+// ImportLayout menerima multipart form: field file (XLSX dengan sheet yards, blocks, plans)
+// dan/atau field yards, blocks, plans (CSV atau XLSX)
+func (h *LayoutImportHandler) ImportLayout(c *fiber.Ctx) error {
+	form, err := c.MultipartForm()
+	if err != nil {
+		utils.ApiResponse(c, http.StatusBadRequest, "Cannot parse form", nil, "Cannot parse multipart form")
+		return nil
+	}
+
+	tables := services.LayoutTables{}
+	found := false
+	for _, kind := range []string{"file", "yards", "blocks", "plans"} {
+		for _, fh := range form.File[kind] {
+			f, err := fh.Open()
+			if err != nil {
+				utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
+				return nil
+			}
+			err = tables.ReadFile(kind, fh.Filename, f)
+			f.Close()
+			if err != nil {
+				utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
+				return nil
+			}
+			found = true
+		}
+	}
+
+	// Validasi input
+	if !found {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: at least one of file, yards, blocks or plans is required")
+		return nil
+	}
+
+	result, err := h.Service.ImportLayout(c.UserContext(), tables, c.QueryBool("dry_run", false))
+	if err != nil {
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error Import Layout", nil, err.Error())
+		return nil
+	}
+	if !result.Valid {
+		utils.ApiResponse(c, http.StatusUnprocessableEntity, "Invalid Layout", result, nil)
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Import Layout Success", result, nil)
+	return nil
+}
@@ -0,0 +1,16 @@
+package handlers
+
+import (
+	"context"
+	"yard-calculation/logging"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// logContext menambah field domain (contoh: nomor kontainer, yard) ke context request, sehingga log di service
+// dan repository untuk request ini ikut membawa field tersebut
+func logContext(c *fiber.Ctx, args ...any) context.Context {
+	ctx := logging.With(c.UserContext(), args...)
+	c.SetUserContext(ctx)
+	return ctx
+}
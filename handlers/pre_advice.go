@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"yard-calculation/logging"
+	"yard-calculation/models"
+	"yard-calculation/schemas"
+	"yard-calculation/services"
+	"yard-calculation/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PreAdviceHandler struct {
+	Service *services.PreAdviceService
+}
+
+func NewPreAdviceHandler(service *services.PreAdviceService) *PreAdviceHandler {
+	return &PreAdviceHandler{Service: service}
+}
+
+func (h *PreAdviceHandler) CreatePreAdvice(c *fiber.Ctx) error {
+	req := new(schemas.CreatePreAdviceRequest)
+	if err := c.BodyParser(req); err != nil {
+		utils.ApiResponse(c, http.StatusBadRequest, "Cannot parse JSON", nil, "Cannot parse JSON")
+		return nil
+	}
+
+	// Validasi input
+	if req.Yard == "" || req.ContainerNumber == "" || (req.ContainerSize != 20 && req.ContainerSize != 40) {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: yard, container_number, and valid size (20 or 40) are required")
+		return nil
+	}
+	if req.ExpectedFrom.IsZero() || req.ExpectedUntil.IsZero() || req.ExpectedUntil.Before(req.ExpectedFrom) {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: expected_from and expected_until are required and expected_until must not be before expected_from")
+		return nil
+	}
+
+	preAdvice, err := h.Service.CreateBooking(logContext(c, logging.KeyYard, req.Yard, logging.KeyContainer, req.ContainerNumber), *req)
+	if err != nil {
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error Create Pre-Advice", nil, err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Create Pre-Advice Success", preAdvice, nil)
+	return nil
+}
+
+func (h *PreAdviceHandler) ListPreAdvices(c *fiber.Ctx) error {
+	req := new(schemas.ListPreAdviceRequest)
+	if err := c.QueryParser(req); err != nil {
+		utils.ApiResponse(c, http.StatusBadRequest, "Cannot parse query", nil, "Cannot parse query")
+		return nil
+	}
+
+	// Validasi input
+	switch req.Status {
+	case "", models.PreAdviceStatusPending, models.PreAdviceStatusArrived, models.PreAdviceStatusPlaced, models.PreAdviceStatusCancelled:
+	default:
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: status must be PENDING, ARRIVED, PLACED or CANCELLED")
+		return nil
+	}
+
+	preAdvices, err := h.Service.ListPreAdvices(c.UserContext(), *req)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid ") {
+			utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
+			return nil
+		}
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error List Pre-Advice", nil, err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "List Pre-Advice", preAdvices, nil)
+	return nil
+}
+
+func (h *PreAdviceHandler) GetPreAdvice(c *fiber.Ctx) error {
+	id, ok := preAdviceID(c)
+	if !ok {
+		return nil
+	}
+
+	preAdvice, err := h.Service.GetPreAdvice(c.UserContext(), id)
+	if err != nil {
+		preAdviceError(c, "Error Get Pre-Advice", id, err)
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Get Pre-Advice", preAdvice, nil)
+	return nil
+}
+
+func (h *PreAdviceHandler) CancelPreAdvice(c *fiber.Ctx) error {
+	id, ok := preAdviceID(c)
+	if !ok {
+		return nil
+	}
+
+	preAdvice, err := h.Service.CancelPreAdvice(c.UserContext(), id)
+	if err != nil {
+		preAdviceError(c, "Error Cancel Pre-Advice", id, err)
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Cancel Pre-Advice Success", preAdvice, nil)
+	return nil
+}
+
+func preAdviceID(c *fiber.Ctx) (uint, bool) {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: pre-advice id must be a positive number")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func preAdviceError(c *fiber.Ctx, message string, id uint, err error) {
+	switch {
+	case err.Error() == fmt.Sprintf("pre-advice %d not found", id):
+		utils.ApiResponse(c, http.StatusNotFound, message, nil, err.Error())
+	case strings.HasPrefix(err.Error(), fmt.Sprintf("pre-advice %d cannot be", id)):
+		utils.ApiResponse(c, http.StatusConflict, message, nil, err.Error())
+	case strings.Contains(err.Error(), " is not allowed to access yard "):
+		utils.ApiResponse(c, http.StatusForbidden, message, nil, err.Error())
+	default:
+		utils.ApiResponse(c, http.StatusInternalServerError, message, nil, err.Error())
+	}
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"yard-calculation/services"
+	"yard-calculation/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type StatisticsHandler struct {
+	Service *services.StatisticsService
+}
+
+func NewStatisticsHandler(service *services.StatisticsService) *StatisticsHandler {
+	return &StatisticsHandler{Service: service}
+}
+
+func (h *StatisticsHandler) GetYardStatistics(c *fiber.Ctx) error {
+	yardName := c.Params("yard")
+
+	stats, err := h.Service.GetYardStatistics(c.UserContext(), yardName)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("yard with name %s not found", yardName) {
+			utils.ApiResponse(c, http.StatusNotFound, "Error Get Statistics", nil, err.Error())
+			return nil
+		}
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error Get Statistics", nil, err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Get Statistics", stats, nil)
+	return nil
+}
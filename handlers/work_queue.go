@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"yard-calculation/logging"
+	"yard-calculation/models"
+	"yard-calculation/schemas"
+	"yard-calculation/services"
+	"yard-calculation/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WorkQueueHandler struct {
+	Service *services.WorkQueueService
+}
+
+func NewWorkQueueHandler(service *services.WorkQueueService) *WorkQueueHandler {
+	return &WorkQueueHandler{Service: service}
+}
+
+func (h *WorkQueueHandler) CreateEquipment(c *fiber.Ctx) error {
+	req := new(schemas.CreateEquipmentRequest)
+	if err := c.BodyParser(req); err != nil {
+		utils.ApiResponse(c, http.StatusBadRequest, "Cannot parse JSON", nil, "Cannot parse JSON")
+		return nil
+	}
+
+	// Validasi input
+	if req.ID == "" || req.Yard == "" || req.Block == "" {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: id, yard, and block are required")
+		return nil
+	}
+
+	equipment, err := h.Service.CreateEquipment(c.UserContext(), *req)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("block with name %s in yard %s not found", req.Block, req.Yard) {
+			utils.ApiResponse(c, http.StatusNotFound, "Error Create Equipment", nil, err.Error())
+			return nil
+		}
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error Create Equipment", nil, err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Create Equipment Success", equipment, nil)
+	return nil
+}
+
+func (h *WorkQueueHandler) ListEquipment(c *fiber.Ctx) error {
+	equipment, err := h.Service.ListEquipment(c.UserContext(), c.Query("yard"))
+	if err != nil {
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error List Equipment", nil, err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "List Equipment", equipment, nil)
+	return nil
+}
+
+func (h *WorkQueueHandler) ListJobs(c *fiber.Ctx) error {
+	req := new(schemas.ListJobsRequest)
+	if err := c.QueryParser(req); err != nil {
+		utils.ApiResponse(c, http.StatusBadRequest, "Cannot parse query", nil, "Cannot parse query")
+		return nil
+	}
+
+	// Validasi input
+	switch req.Status {
+	case "", models.JobStatusPending, models.JobStatusInProgress, models.JobStatusDone, models.JobStatusFailed:
+	default:
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: status must be PENDING, IN_PROGRESS, DONE or FAILED")
+		return nil
+	}
+
+	jobs, err := h.Service.ListJobs(c.UserContext(), *req)
+	if err != nil {
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error List Jobs", nil, err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "List Jobs", jobs, nil)
+	return nil
+}
+
+func (h *WorkQueueHandler) GetJob(c *fiber.Ctx) error {
+	id, ok := jobID(c)
+	if !ok {
+		return nil
+	}
+
+	job, err := h.Service.GetJob(c.UserContext(), id)
+	if err != nil {
+		jobError(c, "Error Get Job", id, err)
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Get Job", job, nil)
+	return nil
+}
+
+func (h *WorkQueueHandler) CreateShiftJob(c *fiber.Ctx) error {
+	req := new(schemas.ShiftContainerRequest)
+	if err := c.BodyParser(req); err != nil {
+		utils.ApiResponse(c, http.StatusBadRequest, "Cannot parse JSON", nil, "Cannot parse JSON")
+		return nil
+	}
+
+	// Validasi input
+	if req.Yard == "" || req.ContainerNumber == "" || req.Block == "" || req.Slot <= 0 || req.Row <= 0 || req.Tier <= 0 {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: yard, container_number, block, and target position are required")
+		return nil
+	}
+
+	job, err := h.Service.CreateShiftJob(logContext(c, logging.KeyYard, req.Yard, logging.KeyContainer, req.ContainerNumber, logging.KeyBlock, req.Block), *req)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("container with number %s not found or not placed", req.ContainerNumber) {
+			utils.ApiResponse(c, http.StatusNotFound, "Error Create Shift Job", nil, err.Error())
+			return nil
+		}
+		utils.ApiResponse(c, http.StatusInternalServerError, "Error Create Shift Job", nil, err.Error())
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Create Shift Job Success", job, nil)
+	return nil
+}
+
+func (h *WorkQueueHandler) StartJob(c *fiber.Ctx) error {
+	id, ok := jobID(c)
+	if !ok {
+		return nil
+	}
+
+	job, err := h.Service.StartJob(c.UserContext(), id)
+	if err != nil {
+		jobError(c, "Error Start Job", id, err)
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Start Job Success", job, nil)
+	return nil
+}
+
+func (h *WorkQueueHandler) CompleteJob(c *fiber.Ctx) error {
+	id, ok := jobID(c)
+	if !ok {
+		return nil
+	}
+
+	job, err := h.Service.CompleteJob(c.UserContext(), id)
+	if err != nil {
+		// Job yang gagal divalidasi ulang tetap dikembalikan supaya operator melihat alasan kegagalannya
+		if job != nil {
+			utils.ApiResponse(c, http.StatusUnprocessableEntity, "Error Complete Job", job, err.Error())
+			return nil
+		}
+		jobError(c, "Error Complete Job", id, err)
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Complete Job Success", job, nil)
+	return nil
+}
+
+func (h *WorkQueueHandler) FailJob(c *fiber.Ctx) error {
+	id, ok := jobID(c)
+	if !ok {
+		return nil
+	}
+
+	req := new(schemas.FailJobRequest)
+	if err := c.BodyParser(req); err != nil {
+		utils.ApiResponse(c, http.StatusBadRequest, "Cannot parse JSON", nil, "Cannot parse JSON")
+		return nil
+	}
+	if req.Reason == "" {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: reason is required")
+		return nil
+	}
+
+	job, err := h.Service.FailJob(c.UserContext(), id, req.Reason)
+	if err != nil {
+		jobError(c, "Error Fail Job", id, err)
+		return nil
+	}
+
+	utils.ApiResponse(c, http.StatusOK, "Fail Job Success", job, nil)
+	return nil
+}
+
+func jobID(c *fiber.Ctx) (uint, bool) {
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		utils.ApiResponse(c, http.StatusBadRequest, "Invalid input", nil, "Invalid input: job id must be a positive number")
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func jobError(c *fiber.Ctx, message string, id uint, err error) {
+	switch {
+	case err.Error() == fmt.Sprintf("job %d not found", id):
+		utils.ApiResponse(c, http.StatusNotFound, message, nil, err.Error())
+	case strings.HasPrefix(err.Error(), fmt.Sprintf("job %d cannot be", id)):
+		utils.ApiResponse(c, http.StatusConflict, message, nil, err.Error())
+	case strings.Contains(err.Error(), " is not allowed to access yard "):
+		utils.ApiResponse(c, http.StatusForbidden, message, nil, err.Error())
+	default:
+		utils.ApiResponse(c, http.StatusInternalServerError, message, nil, err.Error())
+	}
+}
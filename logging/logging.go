@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+)
+
+// Nama field log untuk data domain, dipakai sama di semua layer supaya log bisa difilter per kontainer, yard atau block
+const (
+	KeyRequestID = "request_id"
+	KeyContainer = "container_number"
+	KeyYard      = "yard"
+	KeyBlock     = "block"
+	KeyUser      = "user" // Identitas yang terautentikasi, contoh: api_key:gate-yrd1 atau user:budi
+)
+
+type contextKey int
+
+const (
+	requestIDKey contextKey = iota
+	attrsKey
+)
+
+// Setup memasang logger JSON sebagai default slog dan package log, dengan level debug, info, warn atau error.
+// Setiap baris otomatis membawa request ID dan field domain yang disimpan di context.
+func Setup(level string) {
+	// log.Printf dari library lain juga diteruskan ke slog, waktu sudah ditulis oleh handler JSON
+	log.SetFlags(0)
+	slog.SetDefault(New(os.Stdout, level))
+}
+
+// New membuat logger JSON ke w dengan handler yang membaca field dari context
+func New(w io.Writer, level string) *slog.Logger {
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{Level: parseLevel(level)})
+	return slog.New(contextHandler{handler})
+}
+
+func parseLevel(level string) slog.Level {
+	switch level {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// WithRequestID menyimpan request ID di context
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID mengembalikan request ID dari context, kosong jika tidak ada
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// With menambah field ke context (pasangan key-value seperti slog), semua log dengan context ini membawa field
+// tersebut. Nilai kosong diabaikan, field dengan key yang sama menimpa nilai sebelumnya.
+//
+//	ctx = logging.With(ctx, logging.KeyContainer, "MSKU1234565", logging.KeyYard, "YRD1")
+func With(ctx context.Context, args ...any) context.Context {
+	record := slog.Record{}
+	record.Add(args...)
+
+	attrs := append([]slog.Attr{}, contextAttrs(ctx)...)
+	record.Attrs(func(a slog.Attr) bool {
+		if a.Value.Kind() == slog.KindString && a.Value.String() == "" {
+			return true
+		}
+		for i := range attrs {
+			if attrs[i].Key == a.Key {
+				attrs[i] = a
+				return true
+			}
+		}
+		attrs = append(attrs, a)
+		return true
+	})
+	return context.WithValue(ctx, attrsKey, attrs)
+}
+
+func contextAttrs(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(attrsKey).([]slog.Attr)
+	return attrs
+}
+
+// contextHandler menambah request ID dan field dari With ke setiap record
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil {
+		if id := RequestID(ctx); id != "" {
+			r.AddAttrs(slog.String(KeyRequestID, id))
+		}
+		r.AddAttrs(contextAttrs(ctx)...)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// HeaderRequestID dibaca dari request (contoh: dari gate system atau load balancer) dan dikirim kembali di response
+const HeaderRequestID = "X-Request-ID"
+
+// maxRequestIDLength membatasi request ID dari client supaya log tidak bisa diisi string panjang
+const maxRequestIDLength = 128
+
+// Middleware memberi setiap request sebuah request ID (dari header X-Request-ID atau dibuat baru), menyimpannya di
+// user context fiber dan menulis satu baris access log JSON setelah request selesai. skip (opsional) menentukan
+// path yang tidak ditulis ke access log, contoh: probe Kubernetes.
+func Middleware(skip func(c *fiber.Ctx) bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		requestID := strings.TrimSpace(c.Get(HeaderRequestID))
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = utils.UUIDv4()
+		} else {
+			// Header fiber memakai buffer yang dipakai ulang antar request
+			requestID = strings.Clone(requestID)
+		}
+		c.Set(HeaderRequestID, requestID)
+		c.SetUserContext(WithRequestID(c.UserContext(), requestID))
+
+		err := c.Next()
+		if skip != nil && skip(c) {
+			return err
+		}
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			if e, ok := err.(*fiber.Error); ok {
+				status = e.Code
+			}
+		}
+		level := slog.LevelInfo
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= fiber.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		// UserContext dibaca ulang supaya field domain yang ditambahkan handler ikut tertulis
+		slog.Log(c.UserContext(), level, "request completed",
+			"method", c.Method(),
+			"path", c.Path(),
+			"route", c.Route().Path,
+			"status", status,
+			"duration_ms", float64(time.Since(start).Microseconds())/1000,
+			"ip", c.IP(),
+		)
+		return err
+	}
+}
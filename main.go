@@ -1,52 +1,229 @@
 package main
 
 import (
+	"context"
 	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"yard-calculation/auth"
+	"yard-calculation/cli"
 	"yard-calculation/config"
 	"yard-calculation/handlers"
-	"yard-calculation/models"
+	"yard-calculation/logging"
+	"yard-calculation/metrics"
+	"yard-calculation/migrations"
 	"yard-calculation/repositories"
 	"yard-calculation/services"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 )
 
 func main() {
+	// Konfigurasi dari file YAML dan environment variable, aplikasi berhenti jika ada nilai yang tidak valid
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Log JSON ke stdout, harus dipasang sebelum koneksi database supaya log GORM ikut terstruktur
+	logging.Setup(cfg.Log.Level)
+
 	// Connect to database
-	config.ConnectDatabase()
+	if err := config.ConnectDatabase(cfg); err != nil {
+		log.Fatal(err)
+	}
+
+	// Jalankan subcommand CLI jika ada, contoh: go run main.go import -file layout.xlsx
+	if len(os.Args) > 1 {
+		if err := cli.Run(cfg, os.Args[1], os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
-	// Migrate the schema
-	config.DB.AutoMigrate(&models.Yard{}, &models.Block{}, &models.Container{}, &models.YardPlan{})
+	// Skema dikelola lewat migrasi berversi (go run main.go migrate up), server tidak start jika skema belum terbaru
+	if cfg.Database.MigrateOnStart {
+		applied, err := migrations.Up(config.DB)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, m := range applied {
+			slog.Info("applied migration", "version", m.Version, "name", m.Name)
+		}
+	} else {
+		pending, err := migrations.Pending(config.DB)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(pending) > 0 {
+			log.Fatalf("database has %d pending migration(s), run `go run main.go migrate up` or set DB_MIGRATE_ON_START=true", len(pending))
+		}
+	}
 
 	// Initialize Repository
 	containerRepo := repositories.NewContainerRepository(config.DB)
+	tariffRepo := repositories.NewTariffRepository(config.DB)
+	apiKeyRepo := repositories.NewAPIKeyRepository(config.DB)
+	auditRepo := repositories.NewAuditRepository(config.DB)
 
 	// Initialize Service
-	containerService := services.NewContainerService(containerRepo)
+	// Gauge occupancy per block dihitung di latar belakang setelah ada event kontainer, bukan setiap /metrics dibaca
+	statisticsService := services.NewStatisticsService(containerRepo)
+	occupancyCollector := metrics.NewOccupancyCollector(statisticsService.BlockOccupancies, cfg.HTTP.RequestTimeout)
+	metrics.Registry.MustRegister(occupancyCollector)
+	metricsCtx, stopMetrics := context.WithCancel(context.Background())
+	go occupancyCollector.Run(metricsCtx, cfg.Metrics.OccupancyInterval)
+
+	// Reserver, validator dan listener kontainer dipasang sama seperti di CLI
+	core := services.NewContainerServices(config.DB, cfg, occupancyCollector)
+	containerService := core.Container
+	workQueueService := core.WorkQueue
+	preAdviceService := core.PreAdvice
+	holdService := core.Hold
+	codecoService := core.Codeco
+
+	blockService := services.NewBlockService(containerRepo)
+	housekeepingService := services.NewHousekeepingService(containerService)
+	layoutImportService := services.NewLayoutImportService(containerRepo)
+	exportService := services.NewExportService(containerRepo)
+	vesselService := services.NewVesselService(containerService)
+	billingService := services.NewBillingService(tariffRepo, containerRepo)
+	authService := services.NewAuthService(apiKeyRepo)
+	auditService := services.NewAuditService(auditRepo)
+
+	// Peringatan rencana yang hampir penuh
+	alertConfig := cfg.Alert
+	var notifier services.Notifier = services.LogNotifier{}
+	if alertConfig.WebhookURL != "" {
+		notifier = services.NewWebhookNotifier(alertConfig.WebhookURL)
+	}
+	capacityAlertService := services.NewCapacityAlertService(containerRepo, statisticsService, alertConfig.Threshold, notifier)
+	alertCtx, stopAlert := context.WithCancel(context.Background())
+	if alertConfig.Interval > 0 {
+		capacityAlertService.Start(alertCtx, alertConfig.Interval)
+	}
 
 	// Initialize Handler
+	healthHandler := handlers.NewHealthHandler(config.DB)
 	containerHandler := handlers.NewContainerHandler(containerService)
+	if cfg.Features.WorkQueue {
+		containerHandler.Queue = workQueueService
+	}
+	workQueueHandler := handlers.NewWorkQueueHandler(workQueueService)
+	preAdviceHandler := handlers.NewPreAdviceHandler(preAdviceService)
+	holdHandler := handlers.NewHoldHandler(holdService)
+	billingHandler := handlers.NewBillingHandler(billingService)
+	blockHandler := handlers.NewBlockHandler(blockService)
+	housekeepingHandler := handlers.NewHousekeepingHandler(housekeepingService)
+	statisticsHandler := handlers.NewStatisticsHandler(statisticsService)
+	capacityAlertHandler := handlers.NewCapacityAlertHandler(capacityAlertService)
+	layoutImportHandler := handlers.NewLayoutImportHandler(layoutImportService)
+	exportHandler := handlers.NewExportHandler(exportService)
+	ediHandler := handlers.NewEDIHandler(codecoService, vesselService)
+	auditHandler := handlers.NewAuditHandler(auditService)
 
 	// Initialize Fiber App
-	app := fiber.New()
-	app.Use(logger.New())
+	// Banner startup fiber bukan JSON, diganti satu baris log saat server mulai listen
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	// Request ID dan log per request, probe Kubernetes dan scrape Prometheus tidak perlu memenuhi log
+	app.Use(logging.Middleware(func(c *fiber.Ctx) bool {
+		return c.Path() == "/healthz" || c.Path() == "/readyz" || c.Path() == "/metrics"
+	}))
+	app.Use(metrics.Middleware())
 
-	// Middleware
-	app.Use(cors.New())
+	// Browser hanya boleh memanggil API dari origin yang dikonfigurasi
+	if len(cfg.HTTP.CORSOrigins) > 0 {
+		app.Use(cors.New(cors.Config{
+			AllowOrigins:  strings.Join(cfg.HTTP.CORSOrigins, ","),
+			AllowHeaders:  strings.Join([]string{fiber.HeaderOrigin, fiber.HeaderContentType, fiber.HeaderAccept, fiber.HeaderAuthorization, auth.HeaderAPIKey, logging.HeaderRequestID}, ","),
+			ExposeHeaders: logging.HeaderRequestID,
+		}))
+	}
 
 	// Define Routes
-	app.Post("/suggestion", containerHandler.GetSuggestion)
-	app.Post("/placement", containerHandler.PlaceContainer)
-	app.Post("/pickup", containerHandler.PickupContainer)
-
-	// GORM tidak otomatis membuat indeks unik untuk foreign key.
-	// Kita tambahkan manual jika diperlukan untuk performa.
-	// config.DB.Migrator().CreateIndex(&models.Block{}, "YardID") // Contoh
-	// config.DB.Migrator().CreateIndex(&models.Container{}, "BlockID")
-	// config.DB.Migrator().CreateIndex(&models.Container{}, "IsPlaced") // Berguna untuk query pickup
-	// config.DB.Migrator().CreateIndex(&models.Container{}, "ContainerNumber") // Sudah ada dari tag gorm:"uniqueIndex"
-
-	log.Fatal(app.Listen(":3003"))
+	app.Get("/healthz", healthHandler.Healthz)
+	app.Get("/readyz", healthHandler.Readyz)
+	app.Get("/metrics", metrics.Handler())
+
+	// Route API dibatasi waktu sesuai HTTP_REQUEST_TIMEOUT atau HTTP_ROUTE_TIMEOUTS, butuh API key atau JWT dengan
+	// permission route tersebut, dan setiap request yang mengubah data dicatat di audit log.
+	// Probe dan /metrics di atas tetap terbuka untuk Kubernetes dan Prometheus.
+	api := handlers.Router{App: app, Timeout: cfg.HTTP.Timeout}
+	if cfg.Auth.Enabled {
+		var tokens *auth.JWT
+		if cfg.Auth.JWTSecret != "" {
+			tokens = auth.NewJWT(cfg.Auth.JWTSecret, cfg.Auth.JWTIssuer, cfg.Auth.JWTAudience)
+		}
+		api.Before = append(api.Before, auth.Middleware(authService, tokens))
+	} else {
+		slog.Warn("authentication is disabled, all API endpoints are open")
+	}
+	api.Before = append(api.Before, auth.Audit(auditService, "/suggestion", "/suggestion/batch"))
+	api.Post("/suggestion", auth.Require(auth.PermSuggest), containerHandler.GetSuggestion)
+	api.Post("/suggestion/batch", auth.Require(auth.PermSuggest), containerHandler.GetBatchSuggestion)
+	api.Post("/placement", auth.Require(auth.PermPlace), containerHandler.PlaceContainer)
+	api.Post("/pickup", auth.Require(auth.PermPickup), containerHandler.PickupContainer)
+	api.Get("/containers", auth.Require(auth.PermView), containerHandler.SearchContainers)
+	api.Get("/containers/export", auth.Require(auth.PermView), exportHandler.ExportInventory)
+	api.Get("/containers/:number", auth.Require(auth.PermView), containerHandler.GetContainer)
+	api.Get("/yards/:yard/blocks/:block/map", auth.Require(auth.PermView), blockHandler.GetBlockMap)
+	api.Post("/yards/:yard/blocks/:block/housekeeping", auth.Require(auth.PermPlan), housekeepingHandler.PlanHousekeeping)
+	api.Get("/yards/:yard/statistics", auth.Require(auth.PermView), statisticsHandler.GetYardStatistics)
+	api.Get("/plans/capacity", auth.Require(auth.PermView), capacityAlertHandler.CheckPlanCapacity)
+	api.Post("/layout/import", auth.RequireAllYards(auth.PermPlan), layoutImportHandler.ImportLayout)
+	api.Post("/edi/codeco", auth.Require(auth.PermPlace, auth.PermPickup), ediHandler.IngestCodeco)
+	api.Post("/edi/vessel-discharge", auth.Require(auth.PermPlan), ediHandler.PlanVesselDischarge)
+	api.Post("/equipment", auth.Require(auth.PermPlan), workQueueHandler.CreateEquipment)
+	api.Get("/equipment", auth.Require(auth.PermView), workQueueHandler.ListEquipment)
+	api.Get("/jobs", auth.Require(auth.PermView), workQueueHandler.ListJobs)
+	api.Post("/jobs/shift", auth.Require(auth.PermMove), workQueueHandler.CreateShiftJob)
+	api.Get("/jobs/:id", auth.Require(auth.PermView), workQueueHandler.GetJob)
+	api.Post("/jobs/:id/start", auth.Require(auth.PermJob), workQueueHandler.StartJob)
+	api.Post("/jobs/:id/complete", auth.Require(auth.PermJob), workQueueHandler.CompleteJob)
+	api.Post("/jobs/:id/fail", auth.Require(auth.PermJob), workQueueHandler.FailJob)
+	api.Post("/pre-advices", auth.Require(auth.PermPreAdvice), preAdviceHandler.CreatePreAdvice)
+	api.Get("/pre-advices", auth.Require(auth.PermView), preAdviceHandler.ListPreAdvices)
+	api.Get("/pre-advices/:id", auth.Require(auth.PermView), preAdviceHandler.GetPreAdvice)
+	api.Post("/pre-advices/:id/cancel", auth.Require(auth.PermPreAdvice), preAdviceHandler.CancelPreAdvice)
+	api.Post("/holds", auth.Require(auth.PermHold), holdHandler.AddHold)
+	api.Get("/holds", auth.Require(auth.PermView), holdHandler.ListHolds)
+	api.Post("/holds/:id/release", auth.Require(auth.PermHold), holdHandler.ReleaseHold)
+	api.Post("/tariffs", auth.RequireAllYards(auth.PermTariff), billingHandler.CreateTariff)
+	api.Get("/tariffs", auth.Require(auth.PermView), billingHandler.ListTariffs)
+	api.Delete("/tariffs/:id", auth.RequireAllYards(auth.PermTariff), billingHandler.DeleteTariff)
+	api.Get("/billing/storage", auth.Require(auth.PermView), billingHandler.GetStorageCharges)
+	api.Get("/audit-log", auth.Require(auth.PermAudit), auditHandler.ListAuditEntries)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("server listening", "addr", cfg.HTTP.Addr(), "env", cfg.Env)
+		serverErr <- app.Listen(cfg.HTTP.Addr())
+	}()
+
+	// Tunggu SIGTERM (Kubernetes) atau Ctrl+C, lalu berhenti dengan menyelesaikan request yang sedang berjalan
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	select {
+	case err := <-serverErr:
+		log.Fatal(err)
+	case sig := <-signals:
+		slog.Info("shutting down", "signal", sig.String(), "timeout", cfg.HTTP.ShutdownTimeout.String())
+	}
+
+	healthHandler.SetShuttingDown()
+	stopAlert()
+	stopMetrics()
+	if err := app.ShutdownWithTimeout(cfg.HTTP.ShutdownTimeout); err != nil {
+		slog.Error("error shutting down HTTP server", "error", err)
+	}
+	// Pengecekan berkala dan peringatan yang masih dikirim diselesaikan sebelum koneksi database ditutup
+	capacityAlertService.Wait()
+	if err := config.CloseDatabase(); err != nil {
+		slog.Error("error closing database", "error", err)
+	}
+	slog.Info("shutdown complete")
 }
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// routeUnmatched dipakai sebagai label route untuk request yang tidak cocok dengan route mana pun, supaya path
+// acak (contoh: scanner) tidak menambah label baru
+const routeUnmatched = "unmatched"
+
+// Middleware mencatat latency dan status setiap request per route. Label route memakai pola route
+// (contoh: /jobs/:id), bukan path asli.
+func Middleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		// Error yang dikembalikan handler belum diubah jadi status oleh error handler fiber
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			if e, ok := err.(*fiber.Error); ok {
+				status = e.Code
+			}
+		}
+
+		route := c.Route().Path
+		if status == fiber.StatusNotFound && route == "/" && c.Path() != "/" {
+			route = routeUnmatched
+		}
+		// Method dari fiber memakai buffer yang dipakai ulang antar request, harus disalin sebelum jadi label
+		method := strings.Clone(c.Method())
+		code := strconv.Itoa(status)
+
+		httpRequests.WithLabelValues(method, route, code).Inc()
+		if status >= fiber.StatusBadRequest {
+			httpErrors.WithLabelValues(method, route, code).Inc()
+		}
+		httpDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry berisi semua metric aplikasi, dibaca oleh Prometheus lewat /metrics
+var Registry = prometheus.NewRegistry()
+
+// Hasil dan alasan suggestion. Alasan gagal lain memakai kode batch suggestion (NO_PLAN, PLAN_FULL, dll).
+const (
+	SuggestionSuccess = "success"
+	SuggestionFailure = "failure"
+
+	SuggestionReasonPlanned      = "PLANNED"  // Posisi ditemukan dari rencana yard
+	SuggestionReasonReserved     = "RESERVED" // Kontainer sudah punya posisi yang dipesan
+	SuggestionReasonYardNotFound = "YARD_NOT_FOUND"
+	SuggestionReasonCancelled    = "CANCELLED" // Pencarian dihentikan karena deadline request lewat
+	SuggestionReasonError        = "ERROR"
+)
+
+// Operasi yang diukur waktunya di yard_operation_duration_seconds
+const (
+	OperationFindSuggestedPosition = "find_suggested_position"
+	OperationLoadBlockOccupancy    = "load_block_occupancy"
+)
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Number of HTTP requests by route, method and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_request_errors_total",
+		Help: "Number of HTTP requests answered with status 4xx or 5xx by route, method and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by route and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	suggestions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "yard_suggestions_total",
+		Help: "Number of position suggestions by yard, result and reason, including each container of a batch.",
+	}, []string{"yard", "result", "reason"})
+
+	placements = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "yard_placements_total",
+		Help: "Number of containers placed by yard and block.",
+	}, []string{"yard", "block"})
+
+	pickups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "yard_pickups_total",
+		Help: "Number of containers picked up by yard and block.",
+	}, []string{"yard", "block"})
+
+	operationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "yard_operation_duration_seconds",
+		Help:    "Time spent in yard operations such as FindSuggestedPosition and LoadBlockOccupancy.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpErrors, httpDuration,
+		suggestions, placements, pickups, operationDuration,
+	)
+}
+
+// Handler melayani /metrics dalam format teks Prometheus
+func Handler() fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.HandlerFor(Registry, promhttp.HandlerOpts{}))
+}
+
+// RecordSuggestion mencatat hasil satu suggestion. yard dikosongkan jika yard tidak ditemukan supaya nama yard
+// yang salah ketik tidak menambah label baru.
+func RecordSuggestion(yard, result, reason string) {
+	suggestions.WithLabelValues(yard, result, reason).Inc()
+}
+
+func RecordPlacement(yard, block string) {
+	placements.WithLabelValues(yard, block).Inc()
+}
+
+func RecordPickup(yard, block string) {
+	pickups.WithLabelValues(yard, block).Inc()
+}
+
+type untimedKey struct{}
+
+// Untimed menandai ctx sebagai pekerjaan latar belakang (contoh: refresh gauge occupancy) yang tidak dicatat di
+// yard_operation_duration_seconds, supaya histogram hanya berisi latensi operasi yard
+func Untimed(ctx context.Context) context.Context {
+	return context.WithValue(ctx, untimedKey{}, true)
+}
+
+// IsUntimed mengecek apakah ctx ditandai dengan Untimed
+func IsUntimed(ctx context.Context) bool {
+	untimed, _ := ctx.Value(untimedKey{}).(bool)
+	return untimed
+}
+
+// ObserveDuration mencatat waktu sejak start untuk operasi tertentu, dipakai dengan defer:
+//
+//	defer metrics.ObserveDuration(metrics.OperationLoadBlockOccupancy, time.Now())
+func ObserveDuration(operation string, start time.Time) {
+	operationDuration.WithLabelValues(operation).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// occupancyMaxAge membatasi umur gauge occupancy walaupun tidak ada event kontainer, supaya perubahan di luar event
+// (contoh: import layout yang mengubah kapasitas) tetap terlihat
+const occupancyMaxAge = 5 * time.Minute
+
+// BlockOccupancy adalah jumlah posisi terisi dan kapasitas satu block
+type BlockOccupancy struct {
+	Yard     string
+	Block    string
+	Occupied int
+	Capacity int
+}
+
+var (
+	blockOccupiedDesc = prometheus.NewDesc("yard_block_occupied_positions",
+		"Number of occupied positions (slot, row, tier) in a block.", []string{"yard", "block"}, nil)
+	blockCapacityDesc = prometheus.NewDesc("yard_block_capacity_positions",
+		"Total number of positions (slot, row, tier) in a block.", []string{"yard", "block"}, nil)
+	blockUtilizationDesc = prometheus.NewDesc("yard_block_utilization_ratio",
+		"Occupied positions divided by capacity of a block, between 0 and 1.", []string{"yard", "block"}, nil)
+)
+
+// OccupancyCollector mengirim gauge occupancy semua block dari hasil perhitungan terakhir, sehingga scrape
+// /metrics tidak menjalankan query ke database. Run menghitung ulang di latar belakang setelah ada event
+// kontainer (Invalidate) atau setelah occupancyMaxAge, dan query dihentikan setelah timeout.
+type OccupancyCollector struct {
+	load    func(ctx context.Context) ([]BlockOccupancy, error)
+	timeout time.Duration
+
+	dirty     atomic.Bool
+	mu        sync.RWMutex
+	blocks    []BlockOccupancy
+	refreshed time.Time
+}
+
+func NewOccupancyCollector(load func(ctx context.Context) ([]BlockOccupancy, error), timeout time.Duration) *OccupancyCollector {
+	return &OccupancyCollector{load: load, timeout: timeout}
+}
+
+// Invalidate menandai gauge perlu dihitung ulang pada interval Run berikutnya, dipanggil saat kontainer
+// ditempatkan, diambil atau dipindah
+func (c *OccupancyCollector) Invalidate() {
+	c.dirty.Store(true)
+}
+
+// Run menghitung gauge sekali, lalu setiap interval jika ada perubahan, sampai ctx dibatalkan
+func (c *OccupancyCollector) Run(ctx context.Context, interval time.Duration) {
+	c.refresh(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.mu.RLock()
+			stale := time.Since(c.refreshed) >= occupancyMaxAge
+			c.mu.RUnlock()
+			if c.dirty.Load() || stale {
+				c.refresh(ctx)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *OccupancyCollector) refresh(ctx context.Context) {
+	// Event yang datang selama refresh berjalan ditangkap di refresh berikutnya
+	c.dirty.Store(false)
+	ctx, cancel := context.WithTimeout(Untimed(ctx), c.timeout)
+	defer cancel()
+	blocks, err := c.load(ctx)
+	if err != nil {
+		// Gauge lama tetap dikirim sampai refresh berikutnya berhasil
+		c.dirty.Store(true)
+		slog.ErrorContext(ctx, "error refreshing block occupancy metrics", "error", err)
+		return
+	}
+	c.mu.Lock()
+	c.blocks, c.refreshed = blocks, time.Now()
+	c.mu.Unlock()
+}
+
+func (c *OccupancyCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- blockOccupiedDesc
+	ch <- blockCapacityDesc
+	ch <- blockUtilizationDesc
+}
+
+func (c *OccupancyCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for _, b := range c.blocks {
+		ch <- prometheus.MustNewConstMetric(blockOccupiedDesc, prometheus.GaugeValue, float64(b.Occupied), b.Yard, b.Block)
+		ch <- prometheus.MustNewConstMetric(blockCapacityDesc, prometheus.GaugeValue, float64(b.Capacity), b.Yard, b.Block)
+		ratio := 0.0
+		if b.Capacity > 0 {
+			ratio = float64(b.Occupied) / float64(b.Capacity)
+		}
+		ch <- prometheus.MustNewConstMetric(blockUtilizationDesc, prometheus.GaugeValue, ratio, b.Yard, b.Block)
+	}
+}
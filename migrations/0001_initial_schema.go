@@ -0,0 +1,206 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Snapshot model pada saat migrasi 0001 dibuat. Migrasi tidak memakai package models supaya perubahan model
+// berikutnya tidak mengubah hasil migrasi yang sudah dijalankan.
+
+type yardV1 struct {
+	ID     string `gorm:"primaryKey"`
+	Name   string
+	Blocks []blockV1 `gorm:"foreignKey:YardID"`
+}
+
+func (yardV1) TableName() string { return "yards" }
+
+type blockV1 struct {
+	ID        string `gorm:"primaryKey"`
+	Name      string
+	YardID    string `gorm:"index"`
+	Yard      yardV1 `gorm:"foreignKey:YardID"`
+	TotalSlot int
+	TotalRow  int
+	TotalTier int
+	Plans     []yardPlanV1 `gorm:"foreignKey:BlockID"`
+}
+
+func (blockV1) TableName() string { return "blocks" }
+
+type yardPlanV1 struct {
+	ID            uint   `gorm:"primaryKey"`
+	YardID        string `gorm:"index"`
+	BlockID       string `gorm:"index"`
+	PlannedSize   int
+	PlannedHeight float64
+	PlannedType   string
+	MinSlot       int
+	MaxSlot       int
+	MinRow        int
+	MaxRow        int
+	MinTier       int
+	MaxTier       int
+	Yard          yardV1  `gorm:"foreignKey:YardID"`
+	Block         blockV1 `gorm:"foreignKey:BlockID"`
+}
+
+func (yardPlanV1) TableName() string { return "yard_plans" }
+
+type containerV1 struct {
+	ID              uint   `gorm:"primaryKey"`
+	ContainerNumber string `gorm:"uniqueIndex"`
+	Size            int
+	Height          float64
+	Type            string
+	LineOperator    string
+	YardID          string `gorm:"index"`
+	// Indeks yang dulu hanya ada sebagai komentar di main.go: occupancy block dan query pickup
+	BlockID     string `gorm:"index"`
+	Slot        int
+	Row         int
+	Tier        int
+	IsPlaced    bool `gorm:"index"`
+	CargoStatus string
+	PlacedAt    *time.Time
+	PickedUpAt  *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (containerV1) TableName() string { return "containers" }
+
+type preAdviceV1 struct {
+	ID              uint   `gorm:"primaryKey"`
+	ContainerNumber string `gorm:"index"`
+	YardID          string `gorm:"index"`
+	Size            int
+	Height          float64
+	Type            string
+	SizeType        string
+	LineOperator    string
+	Status          string `gorm:"index"`
+	Source          string
+	MessageRef      string
+	ExpectedFrom    *time.Time
+	ExpectedUntil   *time.Time
+	BlockID         string
+	Slot            int
+	Row             int
+	Tier            int
+	ArrivedAt       *time.Time
+	PlacedAt        *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (preAdviceV1) TableName() string { return "pre_advices" }
+
+type ediOutboxMessageV1 struct {
+	ID              uint   `gorm:"primaryKey"`
+	EventKey        string `gorm:"uniqueIndex"`
+	MessageType     string
+	LineOperator    string
+	ContainerNumber string
+	FileName        string
+	CreatedAt       time.Time
+}
+
+func (ediOutboxMessageV1) TableName() string { return "edi_outbox_messages" }
+
+type equipmentV1 struct {
+	ID        string `gorm:"primaryKey"`
+	Name      string
+	Kind      string
+	YardID    string `gorm:"index"`
+	BlockID   string `gorm:"index"`
+	CreatedAt time.Time
+}
+
+func (equipmentV1) TableName() string { return "equipment" }
+
+type workJobV1 struct {
+	ID              uint   `gorm:"primaryKey"`
+	JobType         string `gorm:"index"`
+	Status          string `gorm:"index"`
+	ContainerNumber string `gorm:"index"`
+	Size            int
+	Height          float64
+	Type            string
+	LineOperator    string
+	CargoStatus     string
+	YardID          string `gorm:"index"`
+	EquipmentID     string `gorm:"index"`
+	FromBlockID     string
+	FromSlot        int
+	FromRow         int
+	FromTier        int
+	ToBlockID       string
+	ToSlot          int
+	ToRow           int
+	ToTier          int
+	FailureReason   string
+	StartedAt       *time.Time
+	CompletedAt     *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (workJobV1) TableName() string { return "work_jobs" }
+
+type holdV1 struct {
+	ID              uint   `gorm:"primaryKey"`
+	ContainerNumber string `gorm:"index"`
+	HoldType        string
+	Reason          string
+	CreatedBy       string
+	ReleasedBy      string
+	ReleaseReason   string
+	ReleasedAt      *time.Time `gorm:"index"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (holdV1) TableName() string { return "holds" }
+
+type storageTariffV1 struct {
+	ID          uint `gorm:"primaryKey"`
+	Name        string
+	Size        int
+	Type        string
+	CargoStatus string
+	FreeDays    int
+	DailyRate   float64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (storageTariffV1) TableName() string { return "storage_tariffs" }
+
+// initialSchema membuat semua tabel yang sebelumnya dibuat AutoMigrate di main.go beserta indeksnya.
+// Up memakai AutoMigrate dari snapshot, sehingga database lama yang dibuat AutoMigrate bisa diadopsi: tabel yang
+// sudah ada dibiarkan dan hanya indeks yang belum ada yang ditambahkan.
+var initialSchema = Migration{
+	Version: "0001",
+	Name:    "initial_schema",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(initialSchemaModels()...)
+	},
+	Down: func(tx *gorm.DB) error {
+		models := initialSchemaModels()
+		// Urutan terbalik supaya tabel yang direferensikan foreign key dihapus terakhir
+		for i, j := 0, len(models)-1; i < j; i, j = i+1, j-1 {
+			models[i], models[j] = models[j], models[i]
+		}
+		return tx.Migrator().DropTable(models...)
+	},
+}
+
+func initialSchemaModels() []interface{} {
+	return []interface{}{
+		&yardV1{}, &blockV1{}, &containerV1{}, &yardPlanV1{}, &preAdviceV1{}, &ediOutboxMessageV1{},
+		&equipmentV1{}, &workJobV1{}, &holdV1{}, &storageTariffV1{},
+	}
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type apiKeyV1 struct {
+	ID         uint   `gorm:"primaryKey"`
+	Name       string `gorm:"uniqueIndex"`
+	Prefix     string
+	KeyHash    string `gorm:"uniqueIndex"`
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (apiKeyV1) TableName() string { return "api_keys" }
+
+// apiKeys membuat tabel API key untuk autentikasi client mesin
+var apiKeys = Migration{
+	Version: "0002",
+	Name:    "api_keys",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().CreateTable(&apiKeyV1{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&apiKeyV1{})
+	},
+}
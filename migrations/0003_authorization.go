@@ -0,0 +1,78 @@
+package migrations
+
+import (
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type apiKeyV2 struct {
+	ID         uint   `gorm:"primaryKey"`
+	Name       string `gorm:"uniqueIndex"`
+	Prefix     string
+	KeyHash    string `gorm:"uniqueIndex"`
+	Role       string
+	Yards      string
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (apiKeyV2) TableName() string { return "api_keys" }
+
+type auditEntryV1 struct {
+	ID              uint   `gorm:"primaryKey"`
+	User            string `gorm:"column:user_name;index"`
+	Method          string
+	Route           string
+	Path            string
+	Status          int
+	Yard            string `gorm:"index"`
+	ContainerNumber string `gorm:"index"`
+	RequestID       string
+	CreatedAt       time.Time `gorm:"index"`
+}
+
+func (auditEntryV1) TableName() string { return "audit_entries" }
+
+// authorization menambah role dan yard ke API key serta tabel audit log. Kunci yang sudah ada sebelumnya tidak diberi
+// role maupun yard, sehingga ditolak 403 sampai diganti kunci baru lewat `apikey create -role ... -yards ...`;
+// menebak hak akses (contoh: supervisor di semua yard) akan memberi kunci gate akses penuh.
+var authorization = Migration{
+	Version: "0003",
+	Name:    "authorization",
+	Up: func(tx *gorm.DB) error {
+		for _, column := range []string{"Role", "Yards"} {
+			if err := tx.Migrator().AddColumn(&apiKeyV2{}, column); err != nil {
+				return err
+			}
+		}
+		// Kolom baru diisi string kosong, bukan NULL, supaya bisa dibaca ke field string
+		if err := tx.Model(&apiKeyV2{}).Where("1 = 1").
+			Updates(map[string]any{"role": "", "yards": ""}).Error; err != nil {
+			return err
+		}
+		var active int64
+		if err := tx.Model(&apiKeyV2{}).Where("revoked_at IS NULL").Count(&active).Error; err != nil {
+			return err
+		}
+		if active > 0 {
+			slog.Warn("existing api keys have no role and are rejected with 403, re-issue them with apikey create -role ... -yards ... and revoke the old keys",
+				"active_api_keys", active)
+		}
+		return tx.Migrator().CreateTable(&auditEntryV1{})
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropTable(&auditEntryV1{}); err != nil {
+			return err
+		}
+		for _, column := range []string{"Role", "Yards"} {
+			if err := tx.Migrator().DropColumn(&apiKeyV2{}, column); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
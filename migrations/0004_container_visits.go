@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type containerV2 struct {
+	ID uint `gorm:"primaryKey"`
+	// Satu baris per kunjungan: nomor yang sama boleh muncul lagi setelah di-pickup, tapi hanya satu yang ditempatkan
+	ContainerNumber string `gorm:"index:idx_containers_container_number;uniqueIndex:idx_containers_placed_number,where:is_placed = true"`
+	Size            int
+	Height          float64
+	Type            string
+	LineOperator    string
+	YardID          string `gorm:"index"`
+	BlockID         string `gorm:"index"`
+	Slot            int
+	Row             int
+	Tier            int
+	IsPlaced        bool `gorm:"index"`
+	CargoStatus     string
+	PlacedAt        *time.Time
+	PickedUpAt      *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (containerV2) TableName() string { return "containers" }
+
+// containerVisits mengganti unique index nomor kontainer dengan unique index yang hanya berlaku untuk kontainer yang
+// sedang ditempatkan, sehingga kontainer yang kembali ke yard dicatat sebagai kunjungan baru dan kunjungan lamanya
+// tetap bisa ditagih. Down gagal jika sudah ada nomor dengan lebih dari satu kunjungan.
+var containerVisits = Migration{
+	Version: "0004",
+	Name:    "container_visits",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropIndex(&containerV1{}, "idx_containers_container_number"); err != nil {
+			return err
+		}
+		for _, index := range []string{"idx_containers_container_number", "idx_containers_placed_number"} {
+			if err := tx.Migrator().CreateIndex(&containerV2{}, index); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, index := range []string{"idx_containers_placed_number", "idx_containers_container_number"} {
+			if err := tx.Migrator().DropIndex(&containerV2{}, index); err != nil {
+				return err
+			}
+		}
+		return tx.Migrator().CreateIndex(&containerV1{}, "idx_containers_container_number")
+	},
+}
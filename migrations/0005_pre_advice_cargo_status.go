@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type preAdviceV2 struct {
+	ID              uint   `gorm:"primaryKey"`
+	ContainerNumber string `gorm:"index"`
+	YardID          string `gorm:"index"`
+	Size            int
+	Height          float64
+	Type            string
+	SizeType        string
+	LineOperator    string
+	CargoStatus     string
+	Status          string `gorm:"index"`
+	Source          string
+	MessageRef      string
+	ExpectedFrom    *time.Time
+	ExpectedUntil   *time.Time
+	BlockID         string
+	Slot            int
+	Row             int
+	Tier            int
+	ArrivedAt       *time.Time
+	PlacedAt        *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+func (preAdviceV2) TableName() string { return "pre_advices" }
+
+// preAdviceCargoStatus menyimpan status full/empty dari CODECO gate-in di pre-advice, supaya penempatan berikutnya
+// memakai status tersebut. Pre-advice lama diisi string kosong (tidak diketahui).
+var preAdviceCargoStatus = Migration{
+	Version: "0005",
+	Name:    "pre_advice_cargo_status",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.Migrator().AddColumn(&preAdviceV2{}, "CargoStatus"); err != nil {
+			return err
+		}
+		// Kolom baru diisi string kosong, bukan NULL, supaya bisa dibaca ke field string
+		return tx.Model(&preAdviceV2{}).Where("1 = 1").Update("cargo_status", "").Error
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropColumn(&preAdviceV2{}, "CargoStatus")
+	},
+}
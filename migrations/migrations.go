@@ -0,0 +1,168 @@
+package migrations
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration adalah satu perubahan skema yang bisa dijalankan (Up) dan dibatalkan (Down). Up dan Down dijalankan
+// di dalam transaksi bersama pencatatan di tabel schema_migrations.
+type Migration struct {
+	Version string // Diurutkan secara leksikal, contoh: 0001
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// all berisi semua migrasi, urut dari yang paling lama. Migrasi yang sudah dirilis tidak boleh diubah,
+// perubahan skema berikutnya ditambahkan sebagai migrasi baru.
+var all = []Migration{
+	initialSchema,
+	apiKeys,
+	authorization,
+	containerVisits,
+	preAdviceCargoStatus,
+}
+
+// SchemaMigration mencatat migrasi yang sudah dijalankan di database
+type SchemaMigration struct {
+	Version   string    `gorm:"primaryKey"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// Status adalah status satu migrasi. Migrasi yang tercatat di database tapi tidak dikenal aplikasi
+// (contoh: dijalankan oleh versi aplikasi yang lebih baru) ditandai Unknown.
+type Status struct {
+	Version   string     `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at"`
+	Unknown   bool       `json:"unknown,omitempty"`
+}
+
+func ensureTable(db *gorm.DB) error {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return fmt.Errorf("error creating schema_migrations table: %v", err)
+	}
+	return nil
+}
+
+func appliedMigrations(db *gorm.DB) (map[string]SchemaMigration, error) {
+	if err := ensureTable(db); err != nil {
+		return nil, err
+	}
+	var rows []SchemaMigration
+	if err := db.Order("version ASC").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[string]SchemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// Pending mengembalikan migrasi yang belum dijalankan, urut sesuai versi
+func Pending(db *gorm.DB) ([]Migration, error) {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+	pending := []Migration{}
+	for _, m := range all {
+		if _, ok := applied[m.Version]; !ok {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// Up menjalankan semua migrasi yang belum dijalankan dan mengembalikan migrasi yang berhasil dijalankan.
+// Jika satu migrasi gagal, migrasi itu dibatalkan seluruhnya dan migrasi berikutnya tidak dijalankan.
+func Up(db *gorm.DB) ([]Migration, error) {
+	pending, err := Pending(db)
+	if err != nil {
+		return nil, err
+	}
+
+	done := []Migration{}
+	for _, m := range pending {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %s_%s failed: %v", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// Rollback membatalkan steps migrasi terakhir yang sudah dijalankan, dari yang paling baru
+func Rollback(db *gorm.DB, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("steps must be at least 1")
+	}
+	if err := ensureTable(db); err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]Migration, len(all))
+	for _, m := range all {
+		known[m.Version] = m
+	}
+
+	var rows []SchemaMigration
+	if err := db.Order("version DESC").Limit(steps).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	done := []Migration{}
+	for _, row := range rows {
+		m, ok := known[row.Version]
+		if !ok {
+			return done, fmt.Errorf("migration %s_%s is not known by this version of the application, cannot roll back", row.Version, row.Name)
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{}, "version = ?", m.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("rollback of migration %s_%s failed: %v", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// GetStatus mengembalikan status semua migrasi yang dikenal, ditambah migrasi tidak dikenal yang tercatat di database
+func GetStatus(db *gorm.DB) ([]Status, error) {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []Status{}
+	for _, m := range all {
+		status := Status{Version: m.Version, Name: m.Name}
+		if row, ok := applied[m.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+			delete(applied, m.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, row := range applied {
+		appliedAt := row.AppliedAt
+		statuses = append(statuses, Status{Version: row.Version, Name: row.Name, AppliedAt: &appliedAt, Unknown: true})
+	}
+	sort.SliceStable(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
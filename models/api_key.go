@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// APIKey adalah kunci statis untuk client mesin (contoh: gate system). Hanya hash SHA-256 kunci yang disimpan,
+// kunci asli ditampilkan sekali saat dibuat. Kunci aktif selama RevokedAt masih kosong. Role dan Yards menentukan
+// operasi dan yard yang boleh diakses pemilik kunci.
+type APIKey struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	Name       string     `json:"name" gorm:"uniqueIndex"`
+	Prefix     string     `json:"prefix"` // Awal kunci untuk mengenali kunci di daftar, bukan rahasia
+	KeyHash    string     `json:"-" gorm:"uniqueIndex"`
+	Role       string     `json:"role"`
+	Yards      string     `json:"yards"` // Dipisah koma, contoh: YRD1,YRD2, atau * untuk semua yard
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
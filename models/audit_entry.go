@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// AuditEntry mencatat satu request yang mengubah data (POST, PUT, PATCH, DELETE) beserta identitas pengirimnya,
+// termasuk request yang ditolak atau gagal
+type AuditEntry struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	User            string    `json:"user" gorm:"column:user_name;index"` // Contoh: api_key:gate-yrd1 atau user:budi
+	Method          string    `json:"method"`
+	Route           string    `json:"route"` // Pola route, contoh: /holds/:id/release
+	Path            string    `json:"path"`
+	Status          int       `json:"status"`
+	Yard            string    `json:"yard,omitempty" gorm:"index"`
+	ContainerNumber string    `json:"container_number,omitempty" gorm:"index"`
+	RequestID       string    `json:"request_id"`
+	CreatedAt       time.Time `json:"created_at" gorm:"index"`
+}
@@ -12,5 +12,6 @@ type Block struct {
 	// Relasi ke rencana
 	Plans []YardPlan `json:"plans" gorm:"foreignKey:BlockID"`
 	// Occupancy tetap untuk runtime
-	Occupancy map[string]bool `json:"-" gorm:"-"` // Key: "slot-row-tier", Value: true jika terisi
+	Occupancy  map[string]bool      `json:"-" gorm:"-"` // Key: "slot-row-tier", Value: true jika terisi
+	OccupiedBy map[string]Container `json:"-" gorm:"-"` // Key: "slot-row-tier", Value: kontainer yang menempati posisi
 }
@@ -1,19 +1,38 @@
 package models
 
+import "time"
+
+// Status muatan kontainer
+const (
+	CargoStatusFull  = "FULL"
+	CargoStatusEmpty = "EMPTY"
+)
+
+// Container adalah satu kunjungan kontainer di yard, dari penempatan sampai pickup. Kontainer yang kembali ke yard
+// mendapat baris baru; nomor kontainer hanya unik di antara kontainer yang sedang ditempatkan.
 type Container struct {
 	ID              uint    `json:"id" gorm:"primaryKey"`
-	ContainerNumber string  `json:"container_number" gorm:"uniqueIndex"`
+	ContainerNumber string  `json:"container_number" gorm:"index:idx_containers_container_number;uniqueIndex:idx_containers_placed_number,where:is_placed = true"`
 	Size            int     `json:"container_size"`   // 20 atau 40
 	Height          float64 `json:"container_height"` // 8.6 atau 9.6
 	Type            string  `json:"container_type"`   // DRY, REEFER, OT, dll
-	YardID          string  `json:"yard_id"`
-	BlockID         string  `json:"block_id"`
+	LineOperator    string  `json:"line_operator"`    // Kode shipping line pemilik kontainer, contoh: MSK
+	YardID          string  `json:"yard_id" gorm:"index"`
+	BlockID         string  `json:"block_id" gorm:"index"`
 	// PlanID untuk mengikat ke rencana tertentu (opsional)
 	// YardPlanID        *uint  `json:"yard_plan_id,omitempty"` // Pointer, bisa null
-	Slot     int  `json:"slot"`
-	Row      int  `json:"row"`
-	Tier     int  `json:"tier"`
-	IsPlaced bool `json:"isplaced"` // Menandakan apakah kontainer saat ini berada di lapangan
+	Slot        int    `json:"slot"`
+	Row         int    `json:"row"`
+	Tier        int    `json:"tier"`
+	IsPlaced    bool   `json:"isplaced" gorm:"index"` // Menandakan apakah kontainer saat ini berada di lapangan
+	CargoStatus string `json:"cargo_status"`          // FULL atau EMPTY, dipakai untuk tarif penumpukan
+	// Waktu kunjungan kontainer di yard. Data lama tanpa PlacedAt memakai CreatedAt, dan tanpa PickedUpAt
+	// memakai UpdatedAt jika sudah tidak ditempatkan.
+	PlacedAt   *time.Time `json:"placed_at"`
+	PickedUpAt *time.Time `json:"picked_up_at"`
+	// Diisi otomatis oleh GORM, CreatedAt = waktu kontainer ditempatkan
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 	// Relasi (opsional)
 	// YardPlan          *YardPlan `json:"yard_plan,omitempty" gorm:"foreignKey:YardPlanID"`
 }
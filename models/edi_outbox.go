@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// EDIOutboxMessage mencatat message EDI yang sudah ditulis ke outbox supaya tidak dikirim dua kali
+type EDIOutboxMessage struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	EventKey        string    `json:"event_key" gorm:"uniqueIndex"` // Contoh: CODECO-IN-15 (jenis, arah, ID kontainer)
+	MessageType     string    `json:"message_type"`
+	LineOperator    string    `json:"line_operator"`
+	ContainerNumber string    `json:"container_number"`
+	FileName        string    `json:"file_name"`
+	CreatedAt       time.Time `json:"created_at"`
+}
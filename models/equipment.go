@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Equipment adalah alat yang bekerja di satu block, contoh: RTG atau reach stacker
+type Equipment struct {
+	ID        string    `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name"`
+	Kind      string    `json:"kind"` // RTG, RS, dll
+	YardID    string    `json:"yard_id" gorm:"index"`
+	BlockID   string    `json:"block_id" gorm:"index"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Jenis hold
+const (
+	HoldTypeCustoms    = "CUSTOMS"    // Ditahan bea cukai
+	HoldTypeLine       = "LINE"       // Ditahan shipping line
+	HoldTypeInspection = "INSPECTION" // Menunggu pemeriksaan fisik
+	HoldTypeUnpaid     = "UNPAID"     // Tagihan belum dibayar
+)
+
+// Hold menahan kontainer agar tidak bisa di-pickup. Hold aktif selama ReleasedAt masih kosong.
+type Hold struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	ContainerNumber string     `json:"container_number" gorm:"index"`
+	HoldType        string     `json:"hold_type"`
+	Reason          string     `json:"reason"`
+	CreatedBy       string     `json:"created_by"`
+	ReleasedBy      string     `json:"released_by,omitempty"`
+	ReleaseReason   string     `json:"release_reason,omitempty"`
+	ReleasedAt      *time.Time `json:"released_at" gorm:"index"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Status pre-advice
+const (
+	PreAdviceStatusPending   = "PENDING"   // Sudah diberitahukan, truk belum tiba
+	PreAdviceStatusArrived   = "ARRIVED"   // Truk sudah gate-in, menunggu penempatan
+	PreAdviceStatusPlaced    = "PLACED"    // Kontainer sudah ditempatkan di yard
+	PreAdviceStatusCancelled = "CANCELLED" // Booking dibatalkan, posisi yang dipesan dilepas
+)
+
+// PreAdvice adalah pemberitahuan kedatangan kontainer ke yard. Booking truk menyimpan jendela waktu kedatangan
+// dan posisi yard yang dipesan (BlockID kosong berarti tidak ada posisi yang dipesan, contoh: dari CODECO).
+type PreAdvice struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	ContainerNumber string     `json:"container_number" gorm:"index"`
+	YardID          string     `json:"yard_id" gorm:"index"`
+	Size            int        `json:"container_size"`   // 20 atau 40
+	Height          float64    `json:"container_height"` // 8.6 atau 9.6
+	Type            string     `json:"container_type"`   // DRY, REEFER, OT, dll
+	SizeType        string     `json:"size_type"`        // Kode ISO 6346, contoh: 22G1
+	LineOperator    string     `json:"line_operator"`
+	CargoStatus     string     `json:"cargo_status"` // FULL atau EMPTY dari CODECO gate-in, kosong jika tidak diketahui
+	Status          string     `json:"status" gorm:"index"`
+	Source          string     `json:"source"`      // Contoh: CODECO, BOOKING
+	MessageRef      string     `json:"message_ref"` // Referensi message asal (UNH)
+	ExpectedFrom    *time.Time `json:"expected_from"`
+	ExpectedUntil   *time.Time `json:"expected_until"`
+	BlockID         string     `json:"block_id"`
+	Slot            int        `json:"slot"`
+	Row             int        `json:"row"`
+	Tier            int        `json:"tier"`
+	ArrivedAt       *time.Time `json:"arrived_at"`
+	PlacedAt        *time.Time `json:"placed_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
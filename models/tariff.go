@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// StorageTariff adalah tarif penumpukan per hari setelah masa bebas. Field kosong (0 atau "") berlaku untuk semua
+// nilai; jika beberapa tarif cocok, tarif yang paling spesifik yang dipakai.
+type StorageTariff struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name"`
+	Size        int       `json:"container_size"` // 20, 40 atau 0 untuk semua ukuran
+	Type        string    `json:"container_type"` // DRY, REEFER, dll atau kosong untuk semua tipe
+	CargoStatus string    `json:"cargo_status"`   // FULL, EMPTY atau kosong untuk keduanya
+	FreeDays    int       `json:"free_days"`
+	DailyRate   float64   `json:"daily_rate"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
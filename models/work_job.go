@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// Jenis job
+const (
+	JobTypePlace  = "PLACE"
+	JobTypePickup = "PICKUP"
+	JobTypeShift  = "SHIFT"
+)
+
+// Status job
+const (
+	JobStatusPending    = "PENDING"
+	JobStatusInProgress = "IN_PROGRESS"
+	JobStatusDone       = "DONE"
+	JobStatusFailed     = "FAILED"
+)
+
+// WorkJob adalah pekerjaan untuk alat di block (RTG / reach stacker). Perubahan posisi di tabel containers
+// baru disimpan saat job dikonfirmasi selesai oleh operator.
+type WorkJob struct {
+	ID              uint    `json:"id" gorm:"primaryKey"`
+	JobType         string  `json:"job_type" gorm:"index"`
+	Status          string  `json:"status" gorm:"index"`
+	ContainerNumber string  `json:"container_number" gorm:"index"`
+	Size            int     `json:"container_size"`
+	Height          float64 `json:"container_height"`
+	Type            string  `json:"container_type"`
+	LineOperator    string  `json:"line_operator"`
+	CargoStatus     string  `json:"cargo_status"`
+	YardID          string  `json:"yard_id" gorm:"index"`
+	EquipmentID     string  `json:"equipment_id" gorm:"index"`
+	// Posisi asal (PICKUP dan SHIFT)
+	FromBlockID string `json:"from_block_id"`
+	FromSlot    int    `json:"from_slot"`
+	FromRow     int    `json:"from_row"`
+	FromTier    int    `json:"from_tier"`
+	// Posisi tujuan (PLACE dan SHIFT)
+	ToBlockID     string     `json:"to_block_id"`
+	ToSlot        int        `json:"to_slot"`
+	ToRow         int        `json:"to_row"`
+	ToTier        int        `json:"to_tier"`
+	FailureReason string     `json:"failure_reason,omitempty"`
+	StartedAt     *time.Time `json:"started_at"`
+	CompletedAt   *time.Time `json:"completed_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
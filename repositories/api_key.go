@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"yard-calculation/models"
+
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository struct {
+	DB *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{DB: db}
+}
+
+func (r *APIKeyRepository) CreateAPIKey(ctx context.Context, key *models.APIKey) error {
+	return r.DB.WithContext(ctx).Create(key).Error
+}
+
+func (r *APIKeyRepository) UpdateAPIKey(ctx context.Context, key *models.APIKey) error {
+	return r.DB.WithContext(ctx).Save(key).Error
+}
+
+// GetAPIKeyByHash mengambil kunci berdasarkan hash, termasuk kunci yang sudah dicabut
+func (r *APIKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.DB.WithContext(ctx).Where("key_hash = ?", keyHash).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("api key not found")
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepository) GetAPIKeyByName(ctx context.Context, name string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.DB.WithContext(ctx).Where("name = ?", name).First(&key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("api key %s not found", name)
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepository) ListAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	if err := r.DB.WithContext(ctx).Order("name ASC").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// TouchAPIKey mencatat waktu terakhir kunci dipakai tanpa mengubah field lain
+func (r *APIKeyRepository) TouchAPIKey(ctx context.Context, id uint, usedAt time.Time) error {
+	return r.DB.WithContext(ctx).Model(&models.APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", usedAt).Error
+}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"yard-calculation/models"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepository struct {
+	DB *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{DB: db}
+}
+
+func (r *AuditRepository) CreateAuditEntry(ctx context.Context, entry *models.AuditEntry) error {
+	return r.DB.WithContext(ctx).Create(entry).Error
+}
+
+// AuditFilter berisi kriteria daftar audit, field kosong diabaikan
+type AuditFilter struct {
+	User            string
+	Yard            string
+	ContainerNumber string
+	From            *time.Time
+	Until           *time.Time // Eksklusif
+	Limit           int
+}
+
+// ListAuditEntries mengambil catatan audit, yang terbaru lebih dulu
+func (r *AuditRepository) ListAuditEntries(ctx context.Context, filter AuditFilter) ([]models.AuditEntry, error) {
+	query := r.DB.WithContext(ctx).Model(&models.AuditEntry{})
+	if filter.User != "" {
+		query = query.Where("user_name = ?", filter.User)
+	}
+	if filter.Yard != "" {
+		query = query.Where("yard = ?", filter.Yard)
+	}
+	if filter.ContainerNumber != "" {
+		query = query.Where("container_number = ?", filter.ContainerNumber)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.Until != nil {
+		query = query.Where("created_at < ?", *filter.Until)
+	}
+
+	var entries []models.AuditEntry
+	if err := query.Order("id DESC").Limit(filter.Limit).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -1,15 +1,19 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sort"
+	"time"
+	"yard-calculation/metrics"
 	"yard-calculation/models"
 
 	"gorm.io/gorm"
 )
 
 type ContainerRepository struct {
+	occupancy
 	DB *gorm.DB
 }
 
@@ -17,10 +21,10 @@ func NewContainerRepository(db *gorm.DB) *ContainerRepository {
 	return &ContainerRepository{DB: db}
 }
 
-func (r *ContainerRepository) GetYardByName(name string) (*models.Yard, error) {
+func (r *ContainerRepository) GetYardByName(ctx context.Context, name string) (*models.Yard, error) {
 	var yard models.Yard
 	// Preload Plans juga
-	if err := r.DB.Preload("Blocks.Plans").First(&yard, "id = ?", name).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Preload("Blocks.Plans").First(&yard, "id = ?", name).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("yard with name %s not found", name)
 		}
@@ -29,9 +33,17 @@ func (r *ContainerRepository) GetYardByName(name string) (*models.Yard, error) {
 	return &yard, nil
 }
 
-func (r *ContainerRepository) GetBlockByName(blockName string, yardID string) (*models.Block, error) {
+func (r *ContainerRepository) GetAllYards(ctx context.Context) ([]models.Yard, error) {
+	var yards []models.Yard
+	if err := r.DB.WithContext(ctx).Preload("Blocks.Plans").Order("id ASC").Find(&yards).Error; err != nil {
+		return nil, err
+	}
+	return yards, nil
+}
+
+func (r *ContainerRepository) GetBlockByName(ctx context.Context, blockName string, yardID string) (*models.Block, error) {
 	var block models.Block
-	if err := r.DB.First(&block, "id = ? AND yard_id = ?", blockName, yardID).Error; err != nil {
+	if err := r.DB.WithContext(ctx).First(&block, "id = ? AND yard_id = ?", blockName, yardID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("block with name %s in yard %s not found", blockName, yardID)
 		}
@@ -41,48 +53,39 @@ func (r *ContainerRepository) GetBlockByName(blockName string, yardID string) (*
 }
 
 // Simulasi pengisian Occupancy dari database
-func (r *ContainerRepository) LoadBlockOccupancy(block *models.Block) error {
+func (r *ContainerRepository) LoadBlockOccupancy(ctx context.Context, block *models.Block) error {
+	if !metrics.IsUntimed(ctx) {
+		defer metrics.ObserveDuration(metrics.OperationLoadBlockOccupancy, time.Now())
+	}
+
 	if block.Occupancy == nil {
 		block.Occupancy = make(map[string]bool)
 	}
+	if block.OccupiedBy == nil {
+		block.OccupiedBy = make(map[string]models.Container)
+	}
 
 	var containers []models.Container
 	// Hanya muat kontainer yang ditempatkan di block ini
-	if err := r.DB.Where("block_id = ? AND is_placed = ?", block.ID, true).Find(&containers).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("block_id = ? AND is_placed = ?", block.ID, true).Find(&containers).Error; err != nil {
 		return err
 	}
 
 	for _, c := range containers {
 		// Untuk kontainer 40ft, tandai dua slot sebagai terisi
 		block.Occupancy[fmt.Sprintf("%d-%d-%d", c.Slot, c.Row, c.Tier)] = true
+		block.OccupiedBy[fmt.Sprintf("%d-%d-%d", c.Slot, c.Row, c.Tier)] = c
 		if c.Size == 40 {
 			block.Occupancy[fmt.Sprintf("%d-%d-%d", c.Slot+1, c.Row, c.Tier)] = true
+			block.OccupiedBy[fmt.Sprintf("%d-%d-%d", c.Slot+1, c.Row, c.Tier)] = c
 		}
 	}
 	return nil
 }
 
-// Simulasi pengecekan apakah posisi kosong
-func (r *ContainerRepository) IsPositionAvailable(block *models.Block, slot, row, tier int) bool {
-	key := fmt.Sprintf("%d-%d-%d", slot, row, tier)
-	occupied, exists := block.Occupancy[key]
-	return !exists || !occupied
-}
-
-// Simulasi pengecekan apakah posisi untuk container 40ft kosong
-func (r *ContainerRepository) IsPositionAvailable40ft(block *models.Block, slot, row, tier int) bool {
-	if slot+1 > block.TotalSlot { // Gunakan TotalSlot
-		return false
-	}
-	if !r.IsPositionAvailable(block, slot, row, tier) || !r.IsPositionAvailable(block, slot+1, row, tier) {
-		return false
-	}
-	return true
-}
-
-func (r *ContainerRepository) GetPlansForSpec(yardID, blockID string, size int, height float64, ctype string) ([]models.YardPlan, error) {
+func (r *ContainerRepository) GetPlansForSpec(ctx context.Context, yardID, blockID string, size int, height float64, ctype string) ([]models.YardPlan, error) {
 	var plans []models.YardPlan
-	if err := r.DB.Where("yard_id = ? AND block_id = ? AND planned_size = ? AND planned_height = ? AND planned_type = ?", yardID, blockID, size, height, ctype).Find(&plans).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("yard_id = ? AND block_id = ? AND planned_size = ? AND planned_height = ? AND planned_type = ?", yardID, blockID, size, height, ctype).Find(&plans).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			// Tidak ada rencana spesifik, kembalikan slice kosong
 			return []models.YardPlan{}, nil
@@ -102,80 +105,52 @@ func (r *ContainerRepository) GetPlansForSpec(yardID, blockID string, size int,
 	return plans, nil
 }
 
-func (r *ContainerRepository) FindSuggestedPosition(yard *models.Yard, size int, height float64, ctype string) (*models.Container, error) {
-	// Iterasi semua blocks di yard
-	for _, block := range yard.Blocks {
-		plans, err := r.GetPlansForSpec(yard.ID, block.ID, size, height, ctype)
-		if err != nil {
-			// Log error dan lanjutkan ke block berikutnya
-			fmt.Printf("Error getting plans for block %s: %v\n", block.ID, err)
-			continue
-		}
-		if len(plans) == 0 {
-			// Tidak ada rencana untuk spesifikasi ini di block ini, lanjutkan
-			continue
-		}
+// GetPlansForBlock mengambil semua rencana di block tanpa melihat spesifikasi
+func (r *ContainerRepository) GetPlansForBlock(ctx context.Context, yardID, blockID string) ([]models.YardPlan, error) {
+	var plans []models.YardPlan
+	if err := r.DB.WithContext(ctx).Where("yard_id = ? AND block_id = ?", yardID, blockID).Order("id ASC").Find(&plans).Error; err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
 
-		if err := r.LoadBlockOccupancy(&block); err != nil {
-			// Log error dan lanjutkan ke block berikutnya
-			fmt.Printf("Error loading occupancy for block %s: %v\n", block.ID, err)
-			continue
-		}
+// FindSuggestedPosition mencari posisi kosong pertama sesuai rencana, lihat findSuggestedPosition
+func (r *ContainerRepository) FindSuggestedPosition(ctx context.Context, yard *models.Yard, size int, height float64, ctype string, preferredBlocks []string) (*models.Container, error) {
+	return findSuggestedPosition(ctx, r, yard, size, height, ctype, preferredBlocks)
+}
 
-		// Iterasi dalam rencana-rencana block ini
-		for _, plan := range plans {
-			// Iterasi dalam area rencana (Tier -> Row -> Slot)
-			for t := plan.MinTier; t <= plan.MaxTier; t++ {
-				for r_idx := plan.MinRow; r_idx <= plan.MaxRow; r_idx++ {
-					for s := plan.MinSlot; s <= plan.MaxSlot; s++ {
-						// Pastikan tetap dalam batas total block
-						if s > block.TotalSlot || r_idx > block.TotalRow || t > block.TotalTier {
-							continue
-						}
-						if size == 20 {
-							if r.IsPositionAvailable(&block, s, r_idx, t) {
-								suggested := &models.Container{
-									YardID:  yard.ID,
-									BlockID: block.ID, // Gunakan block.ID dari iterasi
-									// YardPlanID: &plan.ID, // Jika ingin mengikat ke plan
-									Slot: s, Row: r_idx, Tier: t,
-									Size: size, Height: height, Type: ctype,
-								}
-								return suggested, nil
-							}
-						} else if size == 40 {
-							// Pastikan slot berikutnya juga dalam area rencana dan total block
-							if s+1 > plan.MaxSlot || s+1 > block.TotalSlot {
-								continue
-							}
-							if r.IsPositionAvailable40ft(&block, s, r_idx, t) {
-								suggested := &models.Container{
-									YardID:  yard.ID,
-									BlockID: block.ID, // Gunakan block.ID dari iterasi
-									// YardPlanID: &plan.ID, // Jika ingin mengikat ke plan
-									Slot: s, Row: r_idx, Tier: t,
-									Size: size, Height: height, Type: ctype,
-								}
-								return suggested, nil
-							}
-						}
-					}
-				}
+// ImportLayout menyimpan yard, block dan rencana dalam satu transaksi, semua atau tidak sama sekali
+func (r *ContainerRepository) ImportLayout(ctx context.Context, yards []models.Yard, blocks []models.Block, plans []models.YardPlan) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Omit asosiasi supaya GORM tidak mencoba menyimpan Yard/Block kosong dari relasi
+		for i := range yards {
+			if err := tx.Omit("Blocks").Create(&yards[i]).Error; err != nil {
+				return fmt.Errorf("error creating yard %s: %w", yards[i].ID, err)
 			}
 		}
-	}
-	return nil, fmt.Errorf("no suitable position found within planned areas for container spec (size: %d, height: %.1f, type: %s) in any block of yard %s", size, height, ctype, yard.ID)
+		for i := range blocks {
+			if err := tx.Omit("Yard", "Plans").Create(&blocks[i]).Error; err != nil {
+				return fmt.Errorf("error creating block %s: %w", blocks[i].ID, err)
+			}
+		}
+		for i := range plans {
+			if err := tx.Omit("Yard", "Block").Create(&plans[i]).Error; err != nil {
+				return fmt.Errorf("error creating plan for block %s: %w", plans[i].BlockID, err)
+			}
+		}
+		return nil
+	})
 }
 
-func (r *ContainerRepository) CreateContainer(container *models.Container) error {
+func (r *ContainerRepository) CreateContainer(ctx context.Context, container *models.Container) error {
 	// Pastikan IsPlaced di set true saat ditempatkan
 	container.IsPlaced = true
-	return r.DB.Create(container).Error
+	return r.DB.WithContext(ctx).Create(container).Error
 }
 
-func (r *ContainerRepository) GetContainerByNumber(containerNumber string) (*models.Container, error) {
+func (r *ContainerRepository) GetContainerByNumber(ctx context.Context, containerNumber string) (*models.Container, error) {
 	var container models.Container
-	if err := r.DB.Where("container_number = ? AND is_placed = ?", containerNumber, true).First(&container).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Where("container_number = ? AND is_placed = ?", containerNumber, true).First(&container).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("container with number %s not found or not placed", containerNumber)
 		}
@@ -184,13 +159,142 @@ func (r *ContainerRepository) GetContainerByNumber(containerNumber string) (*mod
 	return &container, nil
 }
 
-func (r *ContainerRepository) UpdateContainer(container *models.Container) error {
+func (r *ContainerRepository) UpdateContainer(ctx context.Context, container *models.Container) error {
 	// Misalnya, saat pickup, set IsPlaced ke false
-	return r.DB.Save(container).Error
+	return r.DB.WithContext(ctx).Save(container).Error
+}
+
+// FindContainerByNumber mengambil kunjungan terakhir kontainer tanpa melihat status IsPlaced
+func (r *ContainerRepository) FindContainerByNumber(ctx context.Context, containerNumber string) (*models.Container, error) {
+	var container models.Container
+	if err := r.DB.WithContext(ctx).Where("container_number = ?", containerNumber).Order("id DESC").First(&container).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("container with number %s not found", containerNumber)
+		}
+		return nil, err
+	}
+	return &container, nil
 }
 
-// Fungsi untuk melepaskan posisi di block occupancy (opsional, bisa juga di update saja)
-func (r *ContainerRepository) ReleasePosition(block *models.Block, slot, row, tier int) {
-	key := fmt.Sprintf("%d-%d-%d", slot, row, tier)
-	delete(block.Occupancy, key)
+// GetContainersAbove mengambil kontainer yang ditumpuk di atas kontainer tertentu (slot yang bersinggungan, row sama, tier lebih tinggi)
+func (r *ContainerRepository) GetContainersAbove(ctx context.Context, container *models.Container) ([]models.Container, error) {
+	lastSlot := container.Slot
+	if container.Size == 40 {
+		lastSlot = container.Slot + 1
+	}
+
+	var candidates []models.Container
+	// Kandidat mulai dari slot-1 karena kontainer 40ft di slot-1 juga menutupi slot ini
+	if err := r.DB.WithContext(ctx).Where("block_id = ? AND is_placed = ? AND \"row\" = ? AND tier > ? AND slot BETWEEN ? AND ?",
+		container.BlockID, true, container.Row, container.Tier, container.Slot-1, lastSlot).
+		Order("tier ASC, slot ASC").Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	above := []models.Container{}
+	for _, c := range candidates {
+		cLastSlot := c.Slot
+		if c.Size == 40 {
+			cLastSlot = c.Slot + 1
+		}
+		if cLastSlot >= container.Slot && c.Slot <= lastSlot {
+			above = append(above, c)
+		}
+	}
+	return above, nil
+}
+
+// ContainerFilter berisi kriteria pencarian kontainer, field kosong diabaikan
+type ContainerFilter struct {
+	YardID      string
+	BlockID     string
+	Type        string
+	Size        int
+	IsPlaced    *bool
+	PlacedFrom  *time.Time // Batas bawah PlacedAt, CreatedAt untuk data lama (inklusif)
+	PlacedUntil *time.Time // Batas atas PlacedAt, CreatedAt untuk data lama (eksklusif)
+	UpdatedFrom *time.Time // Batas bawah UpdatedAt (inklusif)
+	Offset      int
+	Limit       int
+}
+
+func (r *ContainerRepository) SearchContainers(ctx context.Context, filter ContainerFilter) ([]models.Container, int64, error) {
+	query := applyContainerFilter(r.DB.WithContext(ctx).Model(&models.Container{}), filter)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var containers []models.Container
+	if err := query.Order("container_number ASC").Offset(filter.Offset).Limit(filter.Limit).Find(&containers).Error; err != nil {
+		return nil, 0, err
+	}
+	return containers, total, nil
+}
+
+// StreamContainers memanggil fn untuk setiap kontainer yang cocok dengan filter tanpa memuat semuanya ke memori,
+// Offset dan Limit diabaikan
+func (r *ContainerRepository) StreamContainers(ctx context.Context, filter ContainerFilter, fn func(c *models.Container) error) error {
+	rows, err := applyContainerFilter(r.DB.WithContext(ctx).Model(&models.Container{}), filter).Order("id ASC").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c models.Container
+		if err := r.DB.WithContext(ctx).ScanRows(rows, &c); err != nil {
+			return err
+		}
+		if err := fn(&c); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func applyContainerFilter(query *gorm.DB, filter ContainerFilter) *gorm.DB {
+	if filter.YardID != "" {
+		query = query.Where("yard_id = ?", filter.YardID)
+	}
+	if filter.BlockID != "" {
+		query = query.Where("block_id = ?", filter.BlockID)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Size != 0 {
+		query = query.Where("size = ?", filter.Size)
+	}
+	if filter.IsPlaced != nil {
+		query = query.Where("is_placed = ?", *filter.IsPlaced)
+	}
+	if filter.PlacedFrom != nil {
+		query = query.Where("COALESCE(placed_at, created_at) >= ?", *filter.PlacedFrom)
+	}
+	if filter.PlacedUntil != nil {
+		query = query.Where("COALESCE(placed_at, created_at) < ?", *filter.PlacedUntil)
+	}
+	if filter.UpdatedFrom != nil {
+		query = query.Where("updated_at >= ?", *filter.UpdatedFrom)
+	}
+	return query
+}
+
+// GetVisitsInRange mengambil kunjungan kontainer di yard yang beririsan dengan rentang [from, until), yaitu
+// ditempatkan sebelum until dan masih di lapangan atau di-pickup setelah from. lineOperator kosong berarti semua.
+func (r *ContainerRepository) GetVisitsInRange(ctx context.Context, yardID, lineOperator string, from, until time.Time) ([]models.Container, error) {
+	query := r.DB.WithContext(ctx).Where("yard_id = ?", yardID).
+		Where("COALESCE(placed_at, created_at) < ?", until).
+		Where("is_placed = ? OR COALESCE(picked_up_at, updated_at) >= ?", true, from)
+	if lineOperator != "" {
+		query = query.Where("line_operator = ?", lineOperator)
+	}
+
+	var containers []models.Container
+	if err := query.Order("line_operator ASC, container_number ASC").Find(&containers).Error; err != nil {
+		return nil, err
+	}
+	return containers, nil
 }
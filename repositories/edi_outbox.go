@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"context"
+	"yard-calculation/models"
+
+	"gorm.io/gorm"
+)
+
+type EDIOutboxRepository struct {
+	DB *gorm.DB
+}
+
+func NewEDIOutboxRepository(db *gorm.DB) *EDIOutboxRepository {
+	return &EDIOutboxRepository{DB: db}
+}
+
+func (r *EDIOutboxRepository) IsSent(ctx context.Context, eventKey string) (bool, error) {
+	var count int64
+	if err := r.DB.WithContext(ctx).Model(&models.EDIOutboxMessage{}).Where("event_key = ?", eventKey).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// RecordMessage menyimpan catatan message lalu menjalankan write dengan ID catatan sebagai referensi message.
+// Jika write atau commit gagal, catatan dibatalkan sehingga message bisa dicoba lagi; write sebaiknya menulis ke
+// file sementara yang baru dipublikasikan pemanggil setelah RecordMessage berhasil.
+func (r *EDIOutboxRepository) RecordMessage(ctx context.Context, message *models.EDIOutboxMessage, write func(message *models.EDIOutboxMessage) error) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(message).Error; err != nil {
+			return err
+		}
+		if err := write(message); err != nil {
+			return err
+		}
+		return tx.Save(message).Error
+	})
+}
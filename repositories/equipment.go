@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"yard-calculation/models"
+
+	"gorm.io/gorm"
+)
+
+type EquipmentRepository struct {
+	DB *gorm.DB
+}
+
+func NewEquipmentRepository(db *gorm.DB) *EquipmentRepository {
+	return &EquipmentRepository{DB: db}
+}
+
+func (r *EquipmentRepository) CreateEquipment(ctx context.Context, equipment *models.Equipment) error {
+	return r.DB.WithContext(ctx).Create(equipment).Error
+}
+
+func (r *EquipmentRepository) GetEquipment(ctx context.Context, id string) (*models.Equipment, error) {
+	var equipment models.Equipment
+	if err := r.DB.WithContext(ctx).First(&equipment, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("equipment %s not found", id)
+		}
+		return nil, err
+	}
+	return &equipment, nil
+}
+
+// ListEquipment mengambil alat di yard, yardID kosong berarti semua yard
+func (r *EquipmentRepository) ListEquipment(ctx context.Context, yardID string) ([]models.Equipment, error) {
+	var equipment []models.Equipment
+	query := r.DB.WithContext(ctx).Order("id ASC")
+	if yardID != "" {
+		query = query.Where("yard_id = ?", yardID)
+	}
+	if err := query.Find(&equipment).Error; err != nil {
+		return nil, err
+	}
+	return equipment, nil
+}
+
+func (r *EquipmentRepository) GetEquipmentForBlock(ctx context.Context, yardID, blockID string) ([]models.Equipment, error) {
+	var equipment []models.Equipment
+	if err := r.DB.WithContext(ctx).Where("yard_id = ? AND block_id = ?", yardID, blockID).Order("id ASC").Find(&equipment).Error; err != nil {
+		return nil, err
+	}
+	return equipment, nil
+}
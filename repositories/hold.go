@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"yard-calculation/models"
+
+	"gorm.io/gorm"
+)
+
+type HoldRepository struct {
+	DB *gorm.DB
+}
+
+func NewHoldRepository(db *gorm.DB) *HoldRepository {
+	return &HoldRepository{DB: db}
+}
+
+func (r *HoldRepository) CreateHold(ctx context.Context, hold *models.Hold) error {
+	return r.DB.WithContext(ctx).Create(hold).Error
+}
+
+func (r *HoldRepository) UpdateHold(ctx context.Context, hold *models.Hold) error {
+	return r.DB.WithContext(ctx).Save(hold).Error
+}
+
+func (r *HoldRepository) GetHold(ctx context.Context, id uint) (*models.Hold, error) {
+	var hold models.Hold
+	if err := r.DB.WithContext(ctx).First(&hold, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("hold %d not found", id)
+		}
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// HoldFilter berisi kriteria daftar hold, field kosong diabaikan
+type HoldFilter struct {
+	ContainerNumber string
+	HoldType        string
+	Active          *bool
+}
+
+func (r *HoldRepository) ListHolds(ctx context.Context, filter HoldFilter) ([]models.Hold, error) {
+	query := r.DB.WithContext(ctx).Model(&models.Hold{})
+	if filter.ContainerNumber != "" {
+		query = query.Where("container_number = ?", filter.ContainerNumber)
+	}
+	if filter.HoldType != "" {
+		query = query.Where("hold_type = ?", filter.HoldType)
+	}
+	if filter.Active != nil {
+		if *filter.Active {
+			query = query.Where("released_at IS NULL")
+		} else {
+			query = query.Where("released_at IS NOT NULL")
+		}
+	}
+
+	var holds []models.Hold
+	if err := query.Order("id ASC").Find(&holds).Error; err != nil {
+		return nil, err
+	}
+	return holds, nil
+}
+
+// GetActiveHolds mengambil hold kontainer yang belum dilepas
+func (r *HoldRepository) GetActiveHolds(ctx context.Context, containerNumber string) ([]models.Hold, error) {
+	active := true
+	return r.ListHolds(ctx, HoldFilter{ContainerNumber: containerNumber, Active: &active})
+}
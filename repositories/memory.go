@@ -0,0 +1,424 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+	"yard-calculation/models"
+
+	"gorm.io/gorm"
+)
+
+// MemoryRepository adalah implementasi Store di memori dengan perilaku yang sama dengan ContainerRepository:
+// primary key unik dan nomor kontainer unik di antara kontainer yang ditempatkan (gorm.ErrDuplicatedKey), pesan not-found yang sama, ID dan timestamp diisi
+// otomatis saat create, dan data yang dikembalikan adalah salinan. Dipakai untuk test service tanpa database.
+type MemoryRepository struct {
+	occupancy
+	mu         sync.RWMutex
+	yards      []models.Yard // Tanpa Blocks, disusun saat dibaca
+	blocks     []models.Block
+	plans      []models.YardPlan
+	containers []models.Container
+	nextPlanID uint
+	nextID     uint
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{nextPlanID: 1, nextID: 1}
+}
+
+func (r *MemoryRepository) GetYardByName(ctx context.Context, name string) (*models.Yard, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, yard := range r.yards {
+		if yard.ID == name {
+			result := r.withBlocks(yard)
+			return &result, nil
+		}
+	}
+	return nil, fmt.Errorf("yard with name %s not found", name)
+}
+
+func (r *MemoryRepository) GetAllYards(ctx context.Context) ([]models.Yard, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	yards := make([]models.Yard, 0, len(r.yards))
+	for _, yard := range r.yards {
+		yards = append(yards, r.withBlocks(yard))
+	}
+	sort.SliceStable(yards, func(i, j int) bool { return yards[i].ID < yards[j].ID })
+	return yards, nil
+}
+
+// withBlocks menyalin yard beserta block dan rencananya, sama dengan Preload("Blocks.Plans")
+func (r *MemoryRepository) withBlocks(yard models.Yard) models.Yard {
+	yard.Blocks = []models.Block{}
+	for _, block := range r.blocks {
+		if block.YardID != yard.ID {
+			continue
+		}
+		block.Plans = []models.YardPlan{}
+		for _, plan := range r.plans {
+			if plan.BlockID == block.ID {
+				block.Plans = append(block.Plans, plan)
+			}
+		}
+		yard.Blocks = append(yard.Blocks, block)
+	}
+	return yard
+}
+
+func (r *MemoryRepository) GetBlockByName(ctx context.Context, blockName string, yardID string) (*models.Block, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, block := range r.blocks {
+		if block.ID == blockName && block.YardID == yardID {
+			return &block, nil
+		}
+	}
+	return nil, fmt.Errorf("block with name %s in yard %s not found", blockName, yardID)
+}
+
+func (r *MemoryRepository) LoadBlockOccupancy(ctx context.Context, block *models.Block) error {
+	if block.Occupancy == nil {
+		block.Occupancy = make(map[string]bool)
+	}
+	if block.OccupiedBy == nil {
+		block.OccupiedBy = make(map[string]models.Container)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, c := range r.containers {
+		if c.BlockID != block.ID || !c.IsPlaced {
+			continue
+		}
+		block.Occupancy[fmt.Sprintf("%d-%d-%d", c.Slot, c.Row, c.Tier)] = true
+		block.OccupiedBy[fmt.Sprintf("%d-%d-%d", c.Slot, c.Row, c.Tier)] = c
+		if c.Size == 40 {
+			block.Occupancy[fmt.Sprintf("%d-%d-%d", c.Slot+1, c.Row, c.Tier)] = true
+			block.OccupiedBy[fmt.Sprintf("%d-%d-%d", c.Slot+1, c.Row, c.Tier)] = c
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRepository) GetPlansForSpec(ctx context.Context, yardID, blockID string, size int, height float64, ctype string) ([]models.YardPlan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	plans := []models.YardPlan{}
+	for _, plan := range r.plans {
+		if plan.YardID == yardID && plan.BlockID == blockID && plan.PlannedSize == size && plan.PlannedHeight == height && plan.PlannedType == ctype {
+			plans = append(plans, plan)
+		}
+	}
+	sort.SliceStable(plans, func(i, j int) bool {
+		if plans[i].MinTier != plans[j].MinTier {
+			return plans[i].MinTier < plans[j].MinTier
+		}
+		if plans[i].MinRow != plans[j].MinRow {
+			return plans[i].MinRow < plans[j].MinRow
+		}
+		return plans[i].MinSlot < plans[j].MinSlot
+	})
+	return plans, nil
+}
+
+func (r *MemoryRepository) GetPlansForBlock(ctx context.Context, yardID, blockID string) ([]models.YardPlan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	plans := []models.YardPlan{}
+	for _, plan := range r.plans {
+		if plan.YardID == yardID && plan.BlockID == blockID {
+			plans = append(plans, plan)
+		}
+	}
+	return plans, nil
+}
+
+func (r *MemoryRepository) FindSuggestedPosition(ctx context.Context, yard *models.Yard, size int, height float64, ctype string, preferredBlocks []string) (*models.Container, error) {
+	return findSuggestedPosition(ctx, r, yard, size, height, ctype, preferredBlocks)
+}
+
+// ImportLayout memvalidasi semua data sebelum menyimpan, sehingga seperti transaksi GORM tidak ada yang tersimpan
+// jika salah satu gagal
+func (r *MemoryRepository) ImportLayout(ctx context.Context, yards []models.Yard, blocks []models.Block, plans []models.YardPlan) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	yardIDs := make(map[string]bool)
+	for _, yard := range r.yards {
+		yardIDs[yard.ID] = true
+	}
+	for _, yard := range yards {
+		if yardIDs[yard.ID] {
+			return fmt.Errorf("error creating yard %s: %w", yard.ID, gorm.ErrDuplicatedKey)
+		}
+		yardIDs[yard.ID] = true
+	}
+	blockIDs := make(map[string]bool)
+	for _, block := range r.blocks {
+		blockIDs[block.ID] = true
+	}
+	for _, block := range blocks {
+		if blockIDs[block.ID] {
+			return fmt.Errorf("error creating block %s: %w", block.ID, gorm.ErrDuplicatedKey)
+		}
+		blockIDs[block.ID] = true
+	}
+	planIDs := make(map[uint]bool)
+	for _, plan := range r.plans {
+		planIDs[plan.ID] = true
+	}
+	for _, plan := range plans {
+		if plan.ID != 0 && planIDs[plan.ID] {
+			return fmt.Errorf("error creating plan for block %s: %w", plan.BlockID, gorm.ErrDuplicatedKey)
+		}
+		planIDs[plan.ID] = true
+	}
+
+	for _, yard := range yards {
+		yard.Blocks = nil
+		r.yards = append(r.yards, yard)
+	}
+	for _, block := range blocks {
+		block.Yard, block.Plans, block.Occupancy, block.OccupiedBy = models.Yard{}, nil, nil, nil
+		r.blocks = append(r.blocks, block)
+	}
+	for i := range plans {
+		if plans[i].ID == 0 {
+			for planIDs[r.nextPlanID] {
+				r.nextPlanID++
+			}
+			plans[i].ID = r.nextPlanID
+			planIDs[r.nextPlanID] = true
+		}
+		plan := plans[i]
+		plan.Yard, plan.Block = models.Yard{}, models.Block{}
+		r.plans = append(r.plans, plan)
+	}
+	return nil
+}
+
+func (r *MemoryRepository) CreateContainer(ctx context.Context, container *models.Container) error {
+	// Pastikan IsPlaced di set true saat ditempatkan
+	container.IsPlaced = true
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.insertContainer(container)
+}
+
+func (r *MemoryRepository) insertContainer(container *models.Container) error {
+	for _, c := range r.containers {
+		if c.IsPlaced && container.IsPlaced && c.ContainerNumber == container.ContainerNumber {
+			return fmt.Errorf("container_number %s: %w", container.ContainerNumber, gorm.ErrDuplicatedKey)
+		}
+		if container.ID != 0 && c.ID == container.ID {
+			return fmt.Errorf("container id %d: %w", container.ID, gorm.ErrDuplicatedKey)
+		}
+	}
+
+	if container.ID == 0 {
+		for r.hasContainerID(r.nextID) {
+			r.nextID++
+		}
+		container.ID = r.nextID
+	}
+	now := time.Now()
+	if container.CreatedAt.IsZero() {
+		container.CreatedAt = now
+	}
+	if container.UpdatedAt.IsZero() {
+		container.UpdatedAt = now
+	}
+	r.containers = append(r.containers, *container)
+	return nil
+}
+
+func (r *MemoryRepository) hasContainerID(id uint) bool {
+	for _, c := range r.containers {
+		if c.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *MemoryRepository) GetContainerByNumber(ctx context.Context, containerNumber string) (*models.Container, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, c := range r.sortedContainers() {
+		if c.ContainerNumber == containerNumber && c.IsPlaced {
+			return &c, nil
+		}
+	}
+	return nil, fmt.Errorf("container with number %s not found or not placed", containerNumber)
+}
+
+// UpdateContainer sama dengan Save di GORM: update semua field, atau create jika ID belum ada
+func (r *MemoryRepository) UpdateContainer(ctx context.Context, container *models.Container) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if container.ID == 0 || !r.hasContainerID(container.ID) {
+		return r.insertContainer(container)
+	}
+	for _, c := range r.containers {
+		if c.ID != container.ID && c.IsPlaced && container.IsPlaced && c.ContainerNumber == container.ContainerNumber {
+			return fmt.Errorf("container_number %s: %w", container.ContainerNumber, gorm.ErrDuplicatedKey)
+		}
+	}
+	container.UpdatedAt = time.Now()
+	for i := range r.containers {
+		if r.containers[i].ID == container.ID {
+			r.containers[i] = *container
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRepository) FindContainerByNumber(ctx context.Context, containerNumber string) (*models.Container, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	containers := r.sortedContainers()
+	for i := len(containers) - 1; i >= 0; i-- {
+		if containers[i].ContainerNumber == containerNumber {
+			return &containers[i], nil
+		}
+	}
+	return nil, fmt.Errorf("container with number %s not found", containerNumber)
+}
+
+func (r *MemoryRepository) GetContainersAbove(ctx context.Context, container *models.Container) ([]models.Container, error) {
+	lastSlot := container.Slot
+	if container.Size == 40 {
+		lastSlot = container.Slot + 1
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	above := []models.Container{}
+	for _, c := range r.containers {
+		if c.BlockID != container.BlockID || !c.IsPlaced || c.Row != container.Row || c.Tier <= container.Tier {
+			continue
+		}
+		cLastSlot := c.Slot
+		if c.Size == 40 {
+			cLastSlot = c.Slot + 1
+		}
+		if cLastSlot >= container.Slot && c.Slot <= lastSlot {
+			above = append(above, c)
+		}
+	}
+	sort.SliceStable(above, func(i, j int) bool {
+		if above[i].Tier != above[j].Tier {
+			return above[i].Tier < above[j].Tier
+		}
+		return above[i].Slot < above[j].Slot
+	})
+	return above, nil
+}
+
+func (r *MemoryRepository) SearchContainers(ctx context.Context, filter ContainerFilter) ([]models.Container, int64, error) {
+	r.mu.RLock()
+	matched := r.filterContainers(filter)
+	r.mu.RUnlock()
+
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].ContainerNumber < matched[j].ContainerNumber })
+	total := int64(len(matched))
+
+	// Sama dengan OFFSET/LIMIT di SQL, limit negatif berarti tanpa batas
+	if filter.Offset > 0 {
+		matched = matched[min(filter.Offset, len(matched)):]
+	}
+	if filter.Limit >= 0 && filter.Limit < len(matched) {
+		matched = matched[:filter.Limit]
+	}
+	return matched, total, nil
+}
+
+// StreamContainers memanggil fn untuk setiap kontainer yang cocok dengan filter, Offset dan Limit diabaikan
+func (r *MemoryRepository) StreamContainers(ctx context.Context, filter ContainerFilter, fn func(c *models.Container) error) error {
+	r.mu.RLock()
+	matched := r.filterContainers(filter)
+	r.mu.RUnlock()
+
+	for i := range matched {
+		if err := fn(&matched[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *MemoryRepository) GetVisitsInRange(ctx context.Context, yardID, lineOperator string, from, until time.Time) ([]models.Container, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	visits := []models.Container{}
+	for _, c := range r.containers {
+		if c.YardID != yardID || (lineOperator != "" && c.LineOperator != lineOperator) {
+			continue
+		}
+		pickedUpAt := c.UpdatedAt
+		if c.PickedUpAt != nil {
+			pickedUpAt = *c.PickedUpAt
+		}
+		if visitPlacedAt(c).Before(until) && (c.IsPlaced || !pickedUpAt.Before(from)) {
+			visits = append(visits, c)
+		}
+	}
+	sort.SliceStable(visits, func(i, j int) bool {
+		if visits[i].LineOperator != visits[j].LineOperator {
+			return visits[i].LineOperator < visits[j].LineOperator
+		}
+		return visits[i].ContainerNumber < visits[j].ContainerNumber
+	})
+	return visits, nil
+}
+
+// filterContainers menerapkan kriteria applyContainerFilter, hasil urut berdasarkan ID
+func (r *MemoryRepository) filterContainers(filter ContainerFilter) []models.Container {
+	matched := []models.Container{}
+	for _, c := range r.sortedContainers() {
+		if (filter.YardID != "" && c.YardID != filter.YardID) ||
+			(filter.BlockID != "" && c.BlockID != filter.BlockID) ||
+			(filter.Type != "" && c.Type != filter.Type) ||
+			(filter.Size != 0 && c.Size != filter.Size) ||
+			(filter.IsPlaced != nil && c.IsPlaced != *filter.IsPlaced) ||
+			(filter.PlacedFrom != nil && visitPlacedAt(c).Before(*filter.PlacedFrom)) ||
+			(filter.PlacedUntil != nil && !visitPlacedAt(c).Before(*filter.PlacedUntil)) ||
+			(filter.UpdatedFrom != nil && c.UpdatedAt.Before(*filter.UpdatedFrom)) {
+			continue
+		}
+		matched = append(matched, c)
+	}
+	return matched
+}
+
+// visitPlacedAt sama dengan COALESCE(placed_at, created_at) di ContainerRepository
+func visitPlacedAt(c models.Container) time.Time {
+	if c.PlacedAt != nil {
+		return *c.PlacedAt
+	}
+	return c.CreatedAt
+}
+
+func (r *MemoryRepository) sortedContainers() []models.Container {
+	containers := slices.Clone(r.containers)
+	sort.Slice(containers, func(i, j int) bool { return containers[i].ID < containers[j].ID })
+	return containers
+}
@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+	"yard-calculation/logging"
+	"yard-calculation/metrics"
+	"yard-calculation/models"
+)
+
+// occupancy berisi operasi occupancy block yang tidak menyentuh database, di-embed oleh setiap implementasi Store
+type occupancy struct{}
+
+// Simulasi pengecekan apakah posisi kosong
+func (o occupancy) IsPositionAvailable(block *models.Block, slot, row, tier int) bool {
+	key := fmt.Sprintf("%d-%d-%d", slot, row, tier)
+	occupied, exists := block.Occupancy[key]
+	return !exists || !occupied
+}
+
+// GetOccupant mengembalikan kontainer yang menempati posisi, hasil dari LoadBlockOccupancy
+func (o occupancy) GetOccupant(block *models.Block, slot, row, tier int) (*models.Container, bool) {
+	c, exists := block.OccupiedBy[fmt.Sprintf("%d-%d-%d", slot, row, tier)]
+	if !exists {
+		return nil, false
+	}
+	return &c, true
+}
+
+// Simulasi pengecekan apakah posisi untuk container 40ft kosong
+func (o occupancy) IsPositionAvailable40ft(block *models.Block, slot, row, tier int) bool {
+	if slot+1 > block.TotalSlot { // Gunakan TotalSlot
+		return false
+	}
+	if !o.IsPositionAvailable(block, slot, row, tier) || !o.IsPositionAvailable(block, slot+1, row, tier) {
+		return false
+	}
+	return true
+}
+
+// OccupyPosition menandai posisi sebagai terisi di block occupancy tanpa menyimpan ke database
+func (o occupancy) OccupyPosition(block *models.Block, slot, row, tier, size int) {
+	if block.Occupancy == nil {
+		block.Occupancy = make(map[string]bool)
+	}
+	block.Occupancy[fmt.Sprintf("%d-%d-%d", slot, row, tier)] = true
+	if size == 40 {
+		block.Occupancy[fmt.Sprintf("%d-%d-%d", slot+1, row, tier)] = true
+	}
+}
+
+// Fungsi untuk melepaskan posisi di block occupancy (opsional, bisa juga di update saja)
+func (o occupancy) ReleasePosition(block *models.Block, slot, row, tier int) {
+	key := fmt.Sprintf("%d-%d-%d", slot, row, tier)
+	delete(block.Occupancy, key)
+	delete(block.OccupiedBy, key)
+}
+
+// findSuggestedPosition mencari posisi kosong pertama sesuai rencana. Block di preferredBlocks (opsional) dicoba
+// lebih dulu. Occupancy block yang sudah dimuat tidak dimuat ulang, sehingga posisi yang ditandai lewat
+// OccupyPosition tetap dianggap terisi pada pemanggilan berikutnya. Pencarian berhenti dengan error ctx.Err() jika
+// ctx dibatalkan, contoh: client menyerah atau deadline request lewat.
+func findSuggestedPosition(ctx context.Context, r Store, yard *models.Yard, size int, height float64, ctype string, preferredBlocks []string) (*models.Container, error) {
+	defer metrics.ObserveDuration(metrics.OperationFindSuggestedPosition, time.Now())
+
+	// Iterasi semua blocks di yard
+	for _, i := range blockOrder(yard.Blocks, preferredBlocks) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		block := &yard.Blocks[i]
+		plans, err := r.GetPlansForSpec(ctx, yard.ID, block.ID, size, height, ctype)
+		if err != nil {
+			// Log error dan lanjutkan ke block berikutnya
+			slog.ErrorContext(logging.With(ctx, logging.KeyYard, yard.ID, logging.KeyBlock, block.ID), "error getting plans for block", "error", err)
+			continue
+		}
+		if len(plans) == 0 {
+			// Tidak ada rencana untuk spesifikasi ini di block ini, lanjutkan
+			continue
+		}
+
+		if block.Occupancy == nil {
+			if err := r.LoadBlockOccupancy(ctx, block); err != nil {
+				// Log error dan lanjutkan ke block berikutnya
+				slog.ErrorContext(logging.With(ctx, logging.KeyYard, yard.ID, logging.KeyBlock, block.ID), "error loading occupancy for block", "error", err)
+				continue
+			}
+		}
+
+		// Iterasi dalam rencana-rencana block ini
+		for _, plan := range plans {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			// Iterasi dalam area rencana (Tier -> Row -> Slot)
+			for t := plan.MinTier; t <= plan.MaxTier; t++ {
+				for r_idx := plan.MinRow; r_idx <= plan.MaxRow; r_idx++ {
+					for s := plan.MinSlot; s <= plan.MaxSlot; s++ {
+						// Pastikan tetap dalam batas total block
+						if s > block.TotalSlot || r_idx > block.TotalRow || t > block.TotalTier {
+							continue
+						}
+						if size == 20 {
+							if r.IsPositionAvailable(block, s, r_idx, t) {
+								suggested := &models.Container{
+									YardID:  yard.ID,
+									BlockID: block.ID, // Gunakan block.ID dari iterasi
+									// YardPlanID: &plan.ID, // Jika ingin mengikat ke plan
+									Slot: s, Row: r_idx, Tier: t,
+									Size: size, Height: height, Type: ctype,
+								}
+								return suggested, nil
+							}
+						} else if size == 40 {
+							// Pastikan slot berikutnya juga dalam area rencana dan total block
+							if s+1 > plan.MaxSlot || s+1 > block.TotalSlot {
+								continue
+							}
+							if r.IsPositionAvailable40ft(block, s, r_idx, t) {
+								suggested := &models.Container{
+									YardID:  yard.ID,
+									BlockID: block.ID, // Gunakan block.ID dari iterasi
+									// YardPlanID: &plan.ID, // Jika ingin mengikat ke plan
+									Slot: s, Row: r_idx, Tier: t,
+									Size: size, Height: height, Type: ctype,
+								}
+								return suggested, nil
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil, fmt.Errorf("no suitable position found within planned areas for container spec (size: %d, height: %.1f, type: %s) in any block of yard %s", size, height, ctype, yard.ID)
+}
+
+// blockOrder mengembalikan index block dengan block di preferred lebih dulu, sisanya sesuai urutan semula
+func blockOrder(blocks []models.Block, preferred []string) []int {
+	order := make([]int, 0, len(blocks))
+	used := make(map[int]bool)
+	for _, id := range preferred {
+		for i, b := range blocks {
+			if b.ID == id && !used[i] {
+				order = append(order, i)
+				used[i] = true
+			}
+		}
+	}
+	for i := range blocks {
+		if !used[i] {
+			order = append(order, i)
+		}
+	}
+	return order
+}
@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"yard-calculation/models"
+
+	"gorm.io/gorm"
+)
+
+type PreAdviceRepository struct {
+	DB *gorm.DB
+}
+
+func NewPreAdviceRepository(db *gorm.DB) *PreAdviceRepository {
+	return &PreAdviceRepository{DB: db}
+}
+
+// GetOpenPreAdvice mengambil pre-advice yang belum selesai (PENDING atau ARRIVED) untuk kontainer di yard
+func (r *PreAdviceRepository) GetOpenPreAdvice(ctx context.Context, yardID, containerNumber string) (*models.PreAdvice, error) {
+	var preAdvice models.PreAdvice
+	if err := r.DB.WithContext(ctx).Where("yard_id = ? AND container_number = ? AND status IN ?", yardID, containerNumber,
+		[]string{models.PreAdviceStatusPending, models.PreAdviceStatusArrived}).
+		Order("id DESC").First(&preAdvice).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("pre-advice for container %s in yard %s not found", containerNumber, yardID)
+		}
+		return nil, err
+	}
+	return &preAdvice, nil
+}
+
+func (r *PreAdviceRepository) GetPreAdvice(ctx context.Context, id uint) (*models.PreAdvice, error) {
+	var preAdvice models.PreAdvice
+	if err := r.DB.WithContext(ctx).First(&preAdvice, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("pre-advice %d not found", id)
+		}
+		return nil, err
+	}
+	return &preAdvice, nil
+}
+
+// PreAdviceFilter berisi kriteria daftar pre-advice, field kosong diabaikan
+type PreAdviceFilter struct {
+	YardID        string
+	Status        string
+	ExpectedFrom  *time.Time
+	ExpectedUntil *time.Time
+}
+
+func (r *PreAdviceRepository) ListPreAdvices(ctx context.Context, filter PreAdviceFilter) ([]models.PreAdvice, error) {
+	query := r.DB.WithContext(ctx).Model(&models.PreAdvice{})
+	if filter.YardID != "" {
+		query = query.Where("yard_id = ?", filter.YardID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	// Booking yang jendela kedatangannya beririsan dengan rentang filter
+	if filter.ExpectedFrom != nil {
+		query = query.Where("expected_until >= ?", *filter.ExpectedFrom)
+	}
+	if filter.ExpectedUntil != nil {
+		query = query.Where("expected_from <= ?", *filter.ExpectedUntil)
+	}
+
+	var preAdvices []models.PreAdvice
+	if err := query.Order("expected_from ASC, id ASC").Find(&preAdvices).Error; err != nil {
+		return nil, err
+	}
+	return preAdvices, nil
+}
+
+// GetReservingPreAdvices mengambil pre-advice di yard yang masih memegang posisi: sudah tiba, atau belum tiba
+// dan jendela kedatangannya belum lewat dari notAfter
+func (r *PreAdviceRepository) GetReservingPreAdvices(ctx context.Context, yardID string, notAfter time.Time) ([]models.PreAdvice, error) {
+	var preAdvices []models.PreAdvice
+	if err := r.DB.WithContext(ctx).Where("yard_id = ? AND block_id <> ''", yardID).
+		Where("status = ? OR (status = ? AND (expected_until IS NULL OR expected_until >= ?))",
+			models.PreAdviceStatusArrived, models.PreAdviceStatusPending, notAfter).
+		Find(&preAdvices).Error; err != nil {
+		return nil, err
+	}
+	return preAdvices, nil
+}
+
+func (r *PreAdviceRepository) CreatePreAdvice(ctx context.Context, preAdvice *models.PreAdvice) error {
+	return r.DB.WithContext(ctx).Create(preAdvice).Error
+}
+
+func (r *PreAdviceRepository) UpdatePreAdvice(ctx context.Context, preAdvice *models.PreAdvice) error {
+	return r.DB.WithContext(ctx).Save(preAdvice).Error
+}
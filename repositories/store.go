@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+	"time"
+	"yard-calculation/models"
+)
+
+// YardStore menyimpan yard beserta block dan rencananya
+type YardStore interface {
+	// GetYardByName mengambil yard beserta Blocks dan Plans setiap block
+	GetYardByName(ctx context.Context, name string) (*models.Yard, error)
+	GetAllYards(ctx context.Context) ([]models.Yard, error)
+	// ImportLayout menyimpan yard, block dan rencana, semua atau tidak sama sekali
+	ImportLayout(ctx context.Context, yards []models.Yard, blocks []models.Block, plans []models.YardPlan) error
+	FindSuggestedPosition(ctx context.Context, yard *models.Yard, size int, height float64, ctype string, preferredBlocks []string) (*models.Container, error)
+}
+
+// BlockStore mengambil block dan mengelola occupancy runtime-nya
+type BlockStore interface {
+	GetBlockByName(ctx context.Context, blockName string, yardID string) (*models.Block, error)
+	LoadBlockOccupancy(ctx context.Context, block *models.Block) error
+	IsPositionAvailable(block *models.Block, slot, row, tier int) bool
+	IsPositionAvailable40ft(block *models.Block, slot, row, tier int) bool
+	GetOccupant(block *models.Block, slot, row, tier int) (*models.Container, bool)
+	OccupyPosition(block *models.Block, slot, row, tier, size int)
+	ReleasePosition(block *models.Block, slot, row, tier int)
+}
+
+// PlanStore mengambil rencana penempatan
+type PlanStore interface {
+	GetPlansForSpec(ctx context.Context, yardID, blockID string, size int, height float64, ctype string) ([]models.YardPlan, error)
+	GetPlansForBlock(ctx context.Context, yardID, blockID string) ([]models.YardPlan, error)
+}
+
+// ContainerStore menyimpan kunjungan kontainer, nomor kontainer unik di antara kontainer yang ditempatkan
+type ContainerStore interface {
+	CreateContainer(ctx context.Context, container *models.Container) error
+	GetContainerByNumber(ctx context.Context, containerNumber string) (*models.Container, error)
+	FindContainerByNumber(ctx context.Context, containerNumber string) (*models.Container, error)
+	UpdateContainer(ctx context.Context, container *models.Container) error
+	GetContainersAbove(ctx context.Context, container *models.Container) ([]models.Container, error)
+	SearchContainers(ctx context.Context, filter ContainerFilter) ([]models.Container, int64, error)
+	StreamContainers(ctx context.Context, filter ContainerFilter, fn func(c *models.Container) error) error
+	GetVisitsInRange(ctx context.Context, yardID, lineOperator string, from, until time.Time) ([]models.Container, error)
+}
+
+// Store menggabungkan semua store yang dipakai service. Dipenuhi oleh ContainerRepository (GORM) dan
+// MemoryRepository (in-memory, untuk test tanpa database).
+type Store interface {
+	YardStore
+	BlockStore
+	PlanStore
+	ContainerStore
+}
+
+var (
+	_ Store = (*ContainerRepository)(nil)
+	_ Store = (*MemoryRepository)(nil)
+)
@@ -0,0 +1,382 @@
+//go:build cgo
+
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+	"yard-calculation/config"
+	"yard-calculation/migrations"
+	"yard-calculation/models"
+
+	"gorm.io/gorm"
+)
+
+// Test di file ini menjalankan skenario yang sama terhadap MemoryRepository dan ContainerRepository (SQLite) dan
+// memastikan hasilnya sama, supaya test service yang memakai MemoryRepository tetap mewakili perilaku database.
+// Butuh cgo karena driver SQLite memakai github.com/mattn/go-sqlite3.
+
+var base = time.Date(2024, 3, 1, 8, 0, 0, 0, time.Local)
+
+func newSQLiteStore(t *testing.T) *ContainerRepository {
+	t.Helper()
+	cfg := &config.Config{Database: config.DatabaseConfig{
+		Driver:       config.DriverSQLite,
+		Path:         filepath.Join(t.TempDir(), "yard.db"),
+		MaxOpenConns: 1,
+	}}
+	if err := config.ConnectDatabase(cfg); err != nil {
+		t.Fatalf("connect sqlite: %v", err)
+	}
+	db := config.DB
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	if _, err := migrations.Up(db); err != nil {
+		t.Fatalf("migrate sqlite: %v", err)
+	}
+	return NewContainerRepository(db)
+}
+
+func layoutFixture() ([]models.Yard, []models.Block, []models.YardPlan) {
+	yards := []models.Yard{{ID: "YRD2", Name: "Yard 2"}, {ID: "YRD1", Name: "Yard 1"}}
+	blocks := []models.Block{
+		{ID: "A", Name: "Block A", YardID: "YRD1", TotalSlot: 10, TotalRow: 3, TotalTier: 4},
+		{ID: "B", Name: "Block B", YardID: "YRD1", TotalSlot: 5, TotalRow: 2, TotalTier: 3},
+		{ID: "C", Name: "Block C", YardID: "YRD2", TotalSlot: 5, TotalRow: 2, TotalTier: 3},
+	}
+	plans := []models.YardPlan{
+		{YardID: "YRD1", BlockID: "A", PlannedSize: 20, PlannedHeight: 8.6, PlannedType: "DRY", MinSlot: 1, MaxSlot: 5, MinRow: 2, MaxRow: 3, MinTier: 1, MaxTier: 4},
+		{YardID: "YRD1", BlockID: "A", PlannedSize: 20, PlannedHeight: 8.6, PlannedType: "DRY", MinSlot: 1, MaxSlot: 5, MinRow: 1, MaxRow: 1, MinTier: 1, MaxTier: 4},
+		{YardID: "YRD1", BlockID: "A", PlannedSize: 40, PlannedHeight: 9.6, PlannedType: "DRY", MinSlot: 6, MaxSlot: 10, MinRow: 1, MaxRow: 3, MinTier: 1, MaxTier: 4},
+		{YardID: "YRD1", BlockID: "B", PlannedSize: 20, PlannedHeight: 8.6, PlannedType: "REEFER", MinSlot: 1, MaxSlot: 5, MinRow: 1, MaxRow: 2, MinTier: 1, MaxTier: 3},
+		{YardID: "YRD2", BlockID: "C", PlannedSize: 20, PlannedHeight: 8.6, PlannedType: "DRY", MinSlot: 1, MaxSlot: 5, MinRow: 1, MaxRow: 2, MinTier: 1, MaxTier: 3},
+	}
+	return yards, blocks, plans
+}
+
+func containerFixture() []models.Container {
+	at := func(hours int) time.Time { return base.Add(time.Duration(hours) * time.Hour) }
+	return []models.Container{
+		{ContainerNumber: "MSKU0000001", Size: 20, Height: 8.6, Type: "DRY", LineOperator: "MSK", YardID: "YRD1", BlockID: "A", Slot: 1, Row: 1, Tier: 1, CreatedAt: at(0), UpdatedAt: at(0)},
+		{ContainerNumber: "MSKU0000002", Size: 40, Height: 9.6, Type: "DRY", LineOperator: "MSK", YardID: "YRD1", BlockID: "A", Slot: 6, Row: 1, Tier: 1, CreatedAt: at(1), UpdatedAt: at(1)},
+		{ContainerNumber: "CMAU0000003", Size: 20, Height: 8.6, Type: "DRY", LineOperator: "CMA", YardID: "YRD1", BlockID: "A", Slot: 1, Row: 1, Tier: 2, CreatedAt: at(2), UpdatedAt: at(2)},
+		{ContainerNumber: "CMAU0000004", Size: 40, Height: 9.6, Type: "DRY", LineOperator: "CMA", YardID: "YRD1", BlockID: "A", Slot: 5, Row: 1, Tier: 2, CreatedAt: at(3), UpdatedAt: at(3)},
+		{ContainerNumber: "MSKU0000005", Size: 20, Height: 8.6, Type: "REEFER", LineOperator: "MSK", YardID: "YRD1", BlockID: "B", Slot: 1, Row: 1, Tier: 1, CreatedAt: at(4), UpdatedAt: at(4)},
+		{ContainerNumber: "HLCU0000006", Size: 20, Height: 8.6, Type: "DRY", LineOperator: "HLC", YardID: "YRD2", BlockID: "C", Slot: 1, Row: 1, Tier: 1, CreatedAt: at(5), UpdatedAt: at(5)},
+	}
+}
+
+// seed mengisi store dengan layout dan kontainer fixture, MSKU0000005 sudah di-pickup
+func seed(t *testing.T, ctx context.Context, s Store) {
+	t.Helper()
+	yards, blocks, plans := layoutFixture()
+	if err := s.ImportLayout(ctx, yards, blocks, plans); err != nil {
+		t.Fatalf("import layout: %v", err)
+	}
+	for _, c := range containerFixture() {
+		if err := s.CreateContainer(ctx, &c); err != nil {
+			t.Fatalf("create %s: %v", c.ContainerNumber, err)
+		}
+		if c.ContainerNumber == "MSKU0000005" {
+			pickedUp := base.Add(24 * time.Hour)
+			c.IsPlaced, c.PickedUpAt = false, &pickedUp
+			if err := s.UpdateContainer(ctx, &c); err != nil {
+				t.Fatalf("pickup %s: %v", c.ContainerNumber, err)
+			}
+		}
+	}
+}
+
+// clean mengosongkan UpdatedAt yang diisi waktu sekarang oleh UpdateContainer
+func clean(containers ...models.Container) []models.Container {
+	for i := range containers {
+		containers[i].UpdatedAt = time.Time{}
+	}
+	return containers
+}
+
+// errText mengembalikan pesan error, atau "" jika tidak ada error
+func errText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func TestStoreParity(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, ctx context.Context, s Store) any
+	}{
+		{"yards", func(t *testing.T, ctx context.Context, s Store) any {
+			yard, err := s.GetYardByName(ctx, "YRD1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			all, err := s.GetAllYards(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, missing := s.GetYardByName(ctx, "YRD9")
+			return []any{yard, all, errText(missing)}
+		}},
+		{"blocks", func(t *testing.T, ctx context.Context, s Store) any {
+			block, err := s.GetBlockByName(ctx, "B", "YRD1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, otherYard := s.GetBlockByName(ctx, "B", "YRD2")
+			return []any{block, errText(otherYard)}
+		}},
+		{"plans", func(t *testing.T, ctx context.Context, s Store) any {
+			spec, err := s.GetPlansForSpec(ctx, "YRD1", "A", 20, 8.6, "DRY")
+			if err != nil {
+				t.Fatal(err)
+			}
+			none, err := s.GetPlansForSpec(ctx, "YRD1", "A", 20, 9.6, "DRY")
+			if err != nil {
+				t.Fatal(err)
+			}
+			block, err := s.GetPlansForBlock(ctx, "YRD1", "A")
+			if err != nil {
+				t.Fatal(err)
+			}
+			return []any{spec, none, block}
+		}},
+		{"import layout is atomic", func(t *testing.T, ctx context.Context, s Store) any {
+			yards := []models.Yard{{ID: "YRD3", Name: "Yard 3"}}
+			blocks := []models.Block{{ID: "A", Name: "Duplicate", YardID: "YRD3", TotalSlot: 1, TotalRow: 1, TotalTier: 1}}
+			err := s.ImportLayout(ctx, yards, blocks, nil)
+			if !errors.Is(err, gorm.ErrDuplicatedKey) {
+				t.Errorf("import duplicate block: got %v, want gorm.ErrDuplicatedKey", err)
+			}
+			_, missing := s.GetYardByName(ctx, "YRD3")
+			return errText(missing)
+		}},
+		{"find containers", func(t *testing.T, ctx context.Context, s Store) any {
+			placed, err := s.GetContainerByNumber(ctx, "MSKU0000001")
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, notPlaced := s.GetContainerByNumber(ctx, "MSKU0000005")
+			pickedUp, err := s.FindContainerByNumber(ctx, "MSKU0000005")
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, missing := s.FindContainerByNumber(ctx, "XXXU9999999")
+			return []any{clean(*placed, *pickedUp), errText(notPlaced), errText(missing)}
+		}},
+		{"duplicate container number", func(t *testing.T, ctx context.Context, s Store) any {
+			c := containerFixture()[0]
+			c.Slot = 2
+			if err := s.CreateContainer(ctx, &c); !errors.Is(err, gorm.ErrDuplicatedKey) {
+				t.Errorf("create duplicate: got %v, want gorm.ErrDuplicatedKey", err)
+			}
+			other, err := s.GetContainerByNumber(ctx, "MSKU0000002")
+			if err != nil {
+				t.Fatal(err)
+			}
+			other.ContainerNumber = "MSKU0000001"
+			if err := s.UpdateContainer(ctx, other); !errors.Is(err, gorm.ErrDuplicatedKey) {
+				t.Errorf("update to duplicate number: got %v, want gorm.ErrDuplicatedKey", err)
+			}
+			total, _, err := s.SearchContainers(ctx, ContainerFilter{Limit: -1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return clean(total...)
+		}},
+		{"container visits", func(t *testing.T, ctx context.Context, s Store) any {
+			// MSKU0000005 sudah di-pickup di seed, kunjungan kedua boleh dibuat dengan nomor yang sama
+			c := containerFixture()[4]
+			c.BlockID, c.Slot, c.CreatedAt, c.UpdatedAt = "B", 2, base.Add(48*time.Hour), base.Add(48*time.Hour)
+			if err := s.CreateContainer(ctx, &c); err != nil {
+				t.Fatalf("create second visit: %v", err)
+			}
+			again := c
+			again.ID, again.Slot = 0, 3
+			if err := s.CreateContainer(ctx, &again); !errors.Is(err, gorm.ErrDuplicatedKey) {
+				t.Errorf("create second placed visit: got %v, want gorm.ErrDuplicatedKey", err)
+			}
+			latest, err := s.FindContainerByNumber(ctx, "MSKU0000005")
+			if err != nil {
+				t.Fatal(err)
+			}
+			visits, err := s.GetVisitsInRange(ctx, "YRD1", "MSK", base, base.Add(72*time.Hour))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return []any{clean(*latest), clean(visits...)}
+		}},
+		{"search by placed date", func(t *testing.T, ctx context.Context, s Store) any {
+			// placed_at dipakai jika ada, created_at hanya untuk baris lama
+			c, err := s.GetContainerByNumber(ctx, "MSKU0000001")
+			if err != nil {
+				t.Fatal(err)
+			}
+			placedAt := base.Add(10 * time.Hour)
+			c.PlacedAt = &placedAt
+			if err := s.UpdateContainer(ctx, c); err != nil {
+				t.Fatal(err)
+			}
+			from, until := base.Add(3*time.Hour), base.Add(11*time.Hour)
+			results := []any{}
+			for _, filter := range []ContainerFilter{
+				{PlacedFrom: &from, Limit: -1},
+				{PlacedUntil: &from, Limit: -1},
+				{PlacedFrom: &from, PlacedUntil: &until, Limit: -1},
+			} {
+				containers, total, err := s.SearchContainers(ctx, filter)
+				if err != nil {
+					t.Fatal(err)
+				}
+				found := slices.ContainsFunc(containers, func(c models.Container) bool { return c.ContainerNumber == "MSKU0000001" })
+				if want := filter.PlacedFrom != nil; found != want {
+					t.Errorf("filter %+v: MSKU0000001 found = %v, want %v", filter, found, want)
+				}
+				results = append(results, clean(containers...), total)
+			}
+			return results
+		}},
+		{"update container", func(t *testing.T, ctx context.Context, s Store) any {
+			c, err := s.GetContainerByNumber(ctx, "HLCU0000006")
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.Slot, c.Tier = 3, 2
+			if err := s.UpdateContainer(ctx, c); err != nil {
+				t.Fatal(err)
+			}
+			if c.UpdatedAt.Equal(base.Add(5 * time.Hour)) {
+				t.Errorf("UpdatedAt was not refreshed")
+			}
+			updated, err := s.FindContainerByNumber(ctx, "HLCU0000006")
+			if err != nil {
+				t.Fatal(err)
+			}
+			return clean(*updated)
+		}},
+		{"block occupancy", func(t *testing.T, ctx context.Context, s Store) any {
+			block, err := s.GetBlockByName(ctx, "A", "YRD1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := s.LoadBlockOccupancy(ctx, block); err != nil {
+				t.Fatal(err)
+			}
+			occupant, _ := s.GetOccupant(block, 7, 1, 1)
+			return []any{block.Occupancy, occupant.ContainerNumber, s.IsPositionAvailable40ft(block, 7, 1, 2), s.IsPositionAvailable40ft(block, 8, 1, 2)}
+		}},
+		{"containers above", func(t *testing.T, ctx context.Context, s Store) any {
+			bottom, err := s.GetContainerByNumber(ctx, "MSKU0000001")
+			if err != nil {
+				t.Fatal(err)
+			}
+			bottom40, err := s.GetContainerByNumber(ctx, "MSKU0000002")
+			if err != nil {
+				t.Fatal(err)
+			}
+			above, err := s.GetContainersAbove(ctx, bottom)
+			if err != nil {
+				t.Fatal(err)
+			}
+			above40, err := s.GetContainersAbove(ctx, bottom40)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return []any{clean(above...), clean(above40...)}
+		}},
+		{"search containers", func(t *testing.T, ctx context.Context, s Store) any {
+			placed, pickedUp := true, false
+			from := base.Add(time.Hour)
+			results := []any{}
+			for _, filter := range []ContainerFilter{
+				{YardID: "YRD1", Offset: 1, Limit: 2},
+				{YardID: "YRD1", IsPlaced: &placed, Size: 40, Limit: -1},
+				{IsPlaced: &pickedUp, Limit: -1},
+				{Type: "DRY", PlacedFrom: &from, Limit: -1},
+				{Offset: 10, Limit: -1},
+			} {
+				containers, total, err := s.SearchContainers(ctx, filter)
+				if err != nil {
+					t.Fatal(err)
+				}
+				results = append(results, clean(containers...), total)
+			}
+			streamed := []string{}
+			if err := s.StreamContainers(ctx, ContainerFilter{YardID: "YRD1", Limit: 1}, func(c *models.Container) error {
+				streamed = append(streamed, c.ContainerNumber)
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+			return append(results, streamed)
+		}},
+		{"visits in range", func(t *testing.T, ctx context.Context, s Store) any {
+			results := []any{}
+			for _, r := range []struct {
+				line        string
+				from, until time.Time
+			}{
+				{"", base, base.Add(48 * time.Hour)},
+				{"MSK", base.Add(25 * time.Hour), base.Add(48 * time.Hour)},
+				{"CMA", base.Add(-time.Hour), base.Add(2 * time.Hour)},
+			} {
+				visits, err := s.GetVisitsInRange(ctx, "YRD1", r.line, r.from, r.until)
+				if err != nil {
+					t.Fatal(err)
+				}
+				results = append(results, clean(visits...))
+			}
+			return results
+		}},
+		{"suggested position", func(t *testing.T, ctx context.Context, s Store) any {
+			yard, err := s.GetYardByName(ctx, "YRD1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := range yard.Blocks {
+				if err := s.LoadBlockOccupancy(ctx, &yard.Blocks[i]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			first, err := s.FindSuggestedPosition(ctx, yard, 40, 9.6, "DRY", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.OccupyPosition(&yard.Blocks[0], first.Slot, first.Row, first.Tier, 40)
+			second, err := s.FindSuggestedPosition(ctx, yard, 40, 9.6, "DRY", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, noPlan := s.FindSuggestedPosition(ctx, yard, 40, 8.6, "OT", nil)
+			return []any{first, second, errText(noPlan)}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			results := make(map[string]string)
+			for name, s := range map[string]Store{"memory": NewMemoryRepository(), "sqlite": newSQLiteStore(t)} {
+				seed(t, ctx, s)
+				result, err := json.Marshal(tt.run(t, ctx, s))
+				if err != nil {
+					t.Fatal(err)
+				}
+				results[name] = string(result)
+			}
+			if results["memory"] != results["sqlite"] {
+				t.Errorf("memory and sqlite results differ\nmemory: %s\nsqlite: %s", results["memory"], results["sqlite"])
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"yard-calculation/models"
+
+	"gorm.io/gorm"
+)
+
+type TariffRepository struct {
+	DB *gorm.DB
+}
+
+func NewTariffRepository(db *gorm.DB) *TariffRepository {
+	return &TariffRepository{DB: db}
+}
+
+func (r *TariffRepository) CreateTariff(ctx context.Context, tariff *models.StorageTariff) error {
+	return r.DB.WithContext(ctx).Create(tariff).Error
+}
+
+func (r *TariffRepository) GetAllTariffs(ctx context.Context) ([]models.StorageTariff, error) {
+	var tariffs []models.StorageTariff
+	if err := r.DB.WithContext(ctx).Order("id ASC").Find(&tariffs).Error; err != nil {
+		return nil, err
+	}
+	return tariffs, nil
+}
+
+func (r *TariffRepository) DeleteTariff(ctx context.Context, id uint) error {
+	result := r.DB.WithContext(ctx).Delete(&models.StorageTariff{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("tariff %d not found", id)
+	}
+	return nil
+}
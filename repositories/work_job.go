@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"yard-calculation/models"
+
+	"gorm.io/gorm"
+)
+
+var openJobStatuses = []string{models.JobStatusPending, models.JobStatusInProgress}
+
+type WorkJobRepository struct {
+	DB *gorm.DB
+}
+
+func NewWorkJobRepository(db *gorm.DB) *WorkJobRepository {
+	return &WorkJobRepository{DB: db}
+}
+
+func (r *WorkJobRepository) CreateJob(ctx context.Context, job *models.WorkJob) error {
+	return r.DB.WithContext(ctx).Create(job).Error
+}
+
+func (r *WorkJobRepository) UpdateJob(ctx context.Context, job *models.WorkJob) error {
+	return r.DB.WithContext(ctx).Save(job).Error
+}
+
+// TransitionJob menyimpan status job hanya jika status di database masih salah satu dari fromStatuses, sehingga dua
+// request untuk job yang sama tidak bisa sama-sama berhasil. Mengembalikan false jika status sudah diubah request lain.
+func (r *WorkJobRepository) TransitionJob(ctx context.Context, job *models.WorkJob, fromStatuses ...string) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(job).Where("status IN ?", fromStatuses).
+		Select("status", "failure_reason", "started_at", "completed_at").Updates(job)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *WorkJobRepository) GetJob(ctx context.Context, id uint) (*models.WorkJob, error) {
+	var job models.WorkJob
+	if err := r.DB.WithContext(ctx).First(&job, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("job %d not found", id)
+		}
+		return nil, err
+	}
+	return &job, nil
+}
+
+// JobFilter berisi kriteria daftar job, field kosong diabaikan
+type JobFilter struct {
+	YardID      string
+	EquipmentID string
+	Status      string
+}
+
+func (r *WorkJobRepository) ListJobs(ctx context.Context, filter JobFilter) ([]models.WorkJob, error) {
+	query := r.DB.WithContext(ctx).Model(&models.WorkJob{})
+	if filter.YardID != "" {
+		query = query.Where("yard_id = ?", filter.YardID)
+	}
+	if filter.EquipmentID != "" {
+		query = query.Where("equipment_id = ?", filter.EquipmentID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var jobs []models.WorkJob
+	if err := query.Order("id ASC").Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// GetOpenJobForContainer mengambil job PENDING/IN_PROGRESS untuk kontainer, nil jika tidak ada
+func (r *WorkJobRepository) GetOpenJobForContainer(ctx context.Context, containerNumber string) (*models.WorkJob, error) {
+	var jobs []models.WorkJob
+	if err := r.DB.WithContext(ctx).Where("container_number = ? AND status IN ?", containerNumber, openJobStatuses).Limit(1).Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+	return &jobs[0], nil
+}
+
+// GetOpenJobsWithTarget mengambil job PLACE dan SHIFT yang belum selesai di yard, posisi tujuannya dianggap terpesan
+func (r *WorkJobRepository) GetOpenJobsWithTarget(ctx context.Context, yardID string) ([]models.WorkJob, error) {
+	var jobs []models.WorkJob
+	if err := r.DB.WithContext(ctx).Where("yard_id = ? AND job_type IN ? AND status IN ?", yardID, []string{models.JobTypePlace, models.JobTypeShift}, openJobStatuses).
+		Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// CountOpenJobsByEquipment menghitung job yang belum selesai per alat
+func (r *WorkJobRepository) CountOpenJobsByEquipment(ctx context.Context, equipmentIDs []string) (map[string]int64, error) {
+	var rows []struct {
+		EquipmentID string
+		Count       int64
+	}
+	if err := r.DB.WithContext(ctx).Model(&models.WorkJob{}).Select("equipment_id, count(*) as count").
+		Where("equipment_id IN ? AND status IN ?", equipmentIDs, openJobStatuses).
+		Group("equipment_id").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64)
+	for _, row := range rows {
+		counts[row.EquipmentID] = row.Count
+	}
+	return counts, nil
+}
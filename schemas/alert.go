@@ -0,0 +1,17 @@
+package schemas
+
+import "time"
+
+type PlanSaturationAlert struct {
+	Yard      string       `json:"yard"`
+	Threshold float64      `json:"threshold"`
+	Plan      PlanCapacity `json:"plan"`
+	CheckedAt time.Time    `json:"checked_at"`
+}
+
+type PlanCapacityReport struct {
+	Threshold float64        `json:"threshold"`
+	CheckedAt time.Time      `json:"checked_at"`
+	Plans     []PlanCapacity `json:"plans"`
+	Saturated []PlanCapacity `json:"saturated"` // Rencana dengan fill_percent >= threshold
+}
@@ -0,0 +1,10 @@
+package schemas
+
+type ListAuditRequest struct {
+	User            string `query:"user"` // Contoh: api_key:gate-yrd1 atau user:budi
+	Yard            string `query:"yard"`
+	ContainerNumber string `query:"container"`
+	From            string `query:"from"` // Format 2006-01-02, opsional
+	To              string `query:"to"`   // Format 2006-01-02, inklusif, opsional
+	Limit           int    `query:"limit"`
+}
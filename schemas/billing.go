@@ -0,0 +1,52 @@
+package schemas
+
+import "time"
+
+type CreateTariffRequest struct {
+	Name          string  `json:"name"`
+	ContainerSize int     `json:"container_size"` // 0 untuk semua ukuran
+	ContainerType string  `json:"container_type"` // Kosong untuk semua tipe
+	CargoStatus   string  `json:"cargo_status"`   // FULL, EMPTY atau kosong untuk keduanya
+	FreeDays      int     `json:"free_days"`
+	DailyRate     float64 `json:"daily_rate"`
+}
+
+type StorageChargeRequest struct {
+	Yard     string `query:"yard"`
+	Customer string `query:"customer"` // Kode shipping line, kosong untuk semua
+	From     string `query:"from"`     // Format 2006-01-02
+	To       string `query:"to"`       // Format 2006-01-02, inklusif
+}
+
+type ContainerCharge struct {
+	ContainerNumber string     `json:"container_number"`
+	Customer        string     `json:"customer"`
+	Size            int        `json:"container_size"`
+	Type            string     `json:"container_type"`
+	CargoStatus     string     `json:"cargo_status"`
+	PlacedAt        time.Time  `json:"placed_at"`
+	PickedUpAt      *time.Time `json:"picked_up_at"`
+	DwellDays       int        `json:"dwell_days"` // Lama kunjungan sampai pickup atau sampai akhir rentang
+	TariffID        uint       `json:"tariff_id"`
+	FreeDays        int        `json:"free_days"`
+	BillableDays    int        `json:"billable_days"` // Hari berbayar yang jatuh di dalam rentang
+	DailyRate       float64    `json:"daily_rate"`
+	Amount          float64    `json:"amount"`
+	Error           string     `json:"error,omitempty"`
+}
+
+type CustomerCharge struct {
+	Customer     string  `json:"customer"`
+	Containers   int     `json:"containers"`
+	BillableDays int     `json:"billable_days"`
+	Amount       float64 `json:"amount"`
+}
+
+type StorageChargeResponse struct {
+	Yard       string            `json:"yard"`
+	From       string            `json:"from"`
+	To         string            `json:"to"`
+	Total      float64           `json:"total"`
+	Customers  []CustomerCharge  `json:"customers"`
+	Containers []ContainerCharge `json:"containers"`
+}
@@ -0,0 +1,22 @@
+package schemas
+
+type BlockMapCell struct {
+	Slot            int    `json:"slot"`
+	Row             int    `json:"row"`
+	Tier            int    `json:"tier"`
+	ContainerNumber string `json:"container_number,omitempty"`
+	ContainerSize   int    `json:"container_size,omitempty"`
+	ContainerType   string `json:"container_type,omitempty"`
+	IsSecondHalf    bool   `json:"is_second_half"` // true jika cell ini slot kedua dari kontainer 40ft
+	PlanIDs         []uint `json:"plan_ids"`       // YardPlan yang mencakup cell ini
+}
+
+type BlockMapResponse struct {
+	Yard      string `json:"yard"`
+	Block     string `json:"block"`
+	TotalSlot int    `json:"total_slot"`
+	TotalRow  int    `json:"total_row"`
+	TotalTier int    `json:"total_tier"`
+	// Matriks cell dengan indeks [slot-1][row-1][tier-1]
+	Cells [][][]BlockMapCell `json:"cells"`
+}
@@ -1,5 +1,7 @@
 package schemas
 
+import "yard-calculation/models"
+
 // Request
 type SuggestContainerRequest struct {
 	Yard            string  `json:"yard"`
@@ -19,6 +21,8 @@ type PlaceContainerRequest struct {
 	ContainerSize   int     `json:"container_size"`
 	ContainerHeight float64 `json:"container_height"`
 	ContainerType   string  `json:"container_type"`
+	LineOperator    string  `json:"line_operator"` // Opsional, dipakai untuk EDI ke shipping line
+	CargoStatus     string  `json:"cargo_status"`  // FULL atau EMPTY, default dari CODECO gate-in atau FULL
 }
 
 type PickupContainerRequest struct {
@@ -26,6 +30,30 @@ type PickupContainerRequest struct {
 	ContainerNumber string `json:"container_number"`
 }
 
+type BatchSuggestItem struct {
+	ContainerNumber string  `json:"container_number"`
+	ContainerSize   int     `json:"container_size"`
+	ContainerHeight float64 `json:"container_height"`
+	ContainerType   string  `json:"container_type"`
+	// Kontainer dengan group yang sama diusahakan di block yang sama (contoh: POD atau shipping line)
+	Group string `json:"group"`
+}
+
+type BatchSuggestRequest struct {
+	Yard       string             `json:"yard"`
+	Containers []BatchSuggestItem `json:"containers"`
+}
+
+type SearchContainerRequest struct {
+	Yard   string `query:"yard"`
+	Block  string `query:"block"`
+	Type   string `query:"type"`
+	Size   int    `query:"size"`
+	Status string `query:"status"` // placed atau picked_up
+	Page   int    `query:"page"`
+	Limit  int    `query:"limit"`
+}
+
 // Response
 type SuggestContainerResponse struct {
 	Yard  string `json:"yard"`
@@ -34,3 +62,75 @@ type SuggestContainerResponse struct {
 	Row   int    `json:"row"`
 	Tier  int    `json:"tier"`
 }
+
+type ContainerPosition struct {
+	Yard  string `json:"yard"`
+	Block string `json:"block"`
+	Slot  int    `json:"slot"`
+	Row   int    `json:"row"`
+	Tier  int    `json:"tier"`
+}
+
+type PlanSummary struct {
+	ID            uint    `json:"id"`
+	BlockID       string  `json:"block_id"`
+	PlannedSize   int     `json:"planned_size"`
+	PlannedHeight float64 `json:"planned_height"`
+	PlannedType   string  `json:"planned_type"`
+	MinSlot       int     `json:"min_slot"`
+	MaxSlot       int     `json:"max_slot"`
+	MinRow        int     `json:"min_row"`
+	MaxRow        int     `json:"max_row"`
+	MinTier       int     `json:"min_tier"`
+	MaxTier       int     `json:"max_tier"`
+}
+
+type ContainerDetailResponse struct {
+	ContainerNumber string             `json:"container_number"`
+	ContainerSize   int                `json:"container_size"`
+	ContainerHeight float64            `json:"container_height"`
+	ContainerType   string             `json:"container_type"`
+	IsPlaced        bool               `json:"isplaced"`
+	Position        *ContainerPosition `json:"position"`
+	Plan            *PlanSummary       `json:"plan"`
+	StackedAbove    []models.Container `json:"stacked_above"`
+}
+
+type Pagination struct {
+	Page  int   `json:"page"`
+	Limit int   `json:"limit"`
+	Total int64 `json:"total"`
+}
+
+type SearchContainerResponse struct {
+	Containers []models.Container `json:"containers"`
+	Pagination Pagination         `json:"pagination"`
+}
+
+// Alasan kontainer tidak mendapat posisi di batch suggestion
+const (
+	BatchReasonInvalidSpec   = "INVALID_SPEC"
+	BatchReasonDuplicate     = "DUPLICATE_IN_BATCH"
+	BatchReasonAlreadyPlaced = "ALREADY_PLACED"
+	BatchReasonNoPlan        = "NO_PLAN"
+	BatchReasonPlanFull      = "PLAN_FULL"
+	// Posisi yang dipesan untuk kontainer tidak cocok dengan spesifikasi atau tidak lagi ada di rencana
+	BatchReasonReservationMismatch = "RESERVATION_MISMATCH"
+)
+
+type BatchSuggestResult struct {
+	ContainerNumber   string                    `json:"container_number"`
+	Group             string                    `json:"group,omitempty"`
+	SuggestedPosition *SuggestContainerResponse `json:"suggested_position"`
+	Reason            string                    `json:"reason,omitempty"`
+	Error             string                    `json:"error,omitempty"`
+}
+
+type BatchSuggestResponse struct {
+	Yard        string               `json:"yard"`
+	Complete    bool                 `json:"complete"` // true jika semua kontainer mendapat posisi
+	Total       int                  `json:"total"`
+	Allocated   int                  `json:"allocated"`
+	Unallocated int                  `json:"unallocated"`
+	Results     []BatchSuggestResult `json:"results"`
+}
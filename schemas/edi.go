@@ -0,0 +1,31 @@
+package schemas
+
+import "yard-calculation/edifact"
+
+type EDIIngestResponse struct {
+	Messages int                    `json:"messages"`
+	Arrivals int                    `json:"arrivals"`
+	Pickups  int                    `json:"pickups"`
+	Errors   []edifact.SegmentError `json:"errors"`
+}
+
+type DischargeAllocation struct {
+	ContainerNumber   string                    `json:"container_number"`
+	SizeType          string                    `json:"size_type"`
+	WeightKg          float64                   `json:"weight_kg"`
+	POD               string                    `json:"pod"`
+	Stowage           string                    `json:"stowage"`
+	SuggestedPosition *SuggestContainerResponse `json:"suggested_position"`
+	Error             string                    `json:"error,omitempty"`
+}
+
+type DischargePlanResponse struct {
+	Voyage      string                 `json:"voyage"`
+	VesselID    string                 `json:"vessel_id"`
+	VesselName  string                 `json:"vessel_name"`
+	Total       int                    `json:"total"`
+	Allocated   int                    `json:"allocated"`
+	Unallocated int                    `json:"unallocated"`
+	Containers  []DischargeAllocation  `json:"containers"`
+	Errors      []edifact.SegmentError `json:"errors"`
+}
@@ -0,0 +1,23 @@
+package schemas
+
+type ExportInventoryRequest struct {
+	Format string `query:"format"` // csv, jsonl atau xlsx
+	Yard   string `query:"yard"`
+	Block  string `query:"block"`
+	Type   string `query:"type"`
+	From   string `query:"from"` // Tanggal penempatan awal, format 2006-01-02
+	To     string `query:"to"`   // Tanggal penempatan akhir (inklusif), format 2006-01-02
+}
+
+type InventoryRow struct {
+	ContainerNumber string  `json:"container_number"`
+	ContainerSize   int     `json:"container_size"`
+	ContainerHeight float64 `json:"container_height"`
+	ContainerType   string  `json:"container_type"`
+	Yard            string  `json:"yard"`
+	Block           string  `json:"block"`
+	Slot            int     `json:"slot"`
+	Row             int     `json:"row"`
+	Tier            int     `json:"tier"`
+	PlacedAt        string  `json:"placed_at"`
+}
@@ -0,0 +1,19 @@
+package schemas
+
+type CreateHoldRequest struct {
+	ContainerNumber string `json:"container_number"`
+	HoldType        string `json:"hold_type"` // CUSTOMS, LINE, INSPECTION atau UNPAID
+	Reason          string `json:"reason"`
+	CreatedBy       string `json:"created_by"`
+}
+
+type ReleaseHoldRequest struct {
+	ReleasedBy string `json:"released_by"`
+	Reason     string `json:"reason"`
+}
+
+type ListHoldRequest struct {
+	ContainerNumber string `query:"container"`
+	HoldType        string `query:"type"`
+	Status          string `query:"status"` // active atau released
+}
@@ -0,0 +1,28 @@
+package schemas
+
+type HousekeepingRequest struct {
+	MaxMoves int `json:"max_moves"` // Batas jumlah pemindahan, default 20
+	// Pengelompokan stack: spec (ukuran/tinggi/tipe, default) atau line_operator
+	GroupBy string `json:"group_by"`
+	// Rencana target, jika kosong dipakai rencana block yang tersimpan
+	TargetPlans []PlanSummary `json:"target_plans"`
+}
+
+type HousekeepingMove struct {
+	Sequence        int               `json:"sequence"`
+	ContainerNumber string            `json:"container_number"`
+	From            ContainerPosition `json:"from"`
+	To              ContainerPosition `json:"to"`
+	Reason          string            `json:"reason"` // OUT_OF_PLAN atau MIXED_STACK
+}
+
+type HousekeepingResponse struct {
+	Yard            string             `json:"yard"`
+	Block           string             `json:"block"`
+	MaxMoves        int                `json:"max_moves"`
+	MisplacedBefore int                `json:"misplaced_before"` // Kontainer di luar rencana yang sesuai
+	MisplacedAfter  int                `json:"misplaced_after"`
+	RehandlesBefore int                `json:"rehandles_before"` // Perkiraan rehandle: pasangan kontainer beda group yang saling menumpuk
+	RehandlesAfter  int                `json:"rehandles_after"`
+	Moves           []HousekeepingMove `json:"moves"`
+}
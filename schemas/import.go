@@ -0,0 +1,17 @@
+package schemas
+
+type ImportRowError struct {
+	Sheet   string `json:"sheet"` // yards, blocks atau plans
+	Row     int    `json:"row"`   // Nomor baris di file, header = baris 1
+	Message string `json:"message"`
+}
+
+type ImportLayoutResponse struct {
+	DryRun    bool             `json:"dry_run"`
+	Valid     bool             `json:"valid"`
+	Committed bool             `json:"committed"`
+	Yards     int              `json:"yards"`
+	Blocks    int              `json:"blocks"`
+	Plans     int              `json:"plans"`
+	Errors    []ImportRowError `json:"errors"`
+}
@@ -0,0 +1,21 @@
+package schemas
+
+import "time"
+
+type CreatePreAdviceRequest struct {
+	Yard            string    `json:"yard"`
+	ContainerNumber string    `json:"container_number"`
+	ContainerSize   int       `json:"container_size"`
+	ContainerHeight float64   `json:"container_height"`
+	ContainerType   string    `json:"container_type"`
+	LineOperator    string    `json:"line_operator"`
+	ExpectedFrom    time.Time `json:"expected_from"`
+	ExpectedUntil   time.Time `json:"expected_until"`
+}
+
+type ListPreAdviceRequest struct {
+	Yard   string `query:"yard"`
+	Status string `query:"status"`
+	From   string `query:"from"` // Tanggal kedatangan awal, format 2006-01-02
+	To     string `query:"to"`   // Tanggal kedatangan akhir (inklusif), format 2006-01-02
+}
@@ -0,0 +1,38 @@
+package schemas
+
+type PlanCapacity struct {
+	PlanID        uint    `json:"plan_id"`
+	BlockID       string  `json:"block_id"`
+	PlannedSize   int     `json:"planned_size"`
+	PlannedHeight float64 `json:"planned_height"`
+	PlannedType   string  `json:"planned_type"`
+	TotalPosition int     `json:"total_position"` // Jumlah cell (TEU) di area rencana
+	FreePosition  int     `json:"free_position"`  // Jumlah cell (TEU) yang masih kosong
+	Remaining20ft int     `json:"remaining_20ft"` // Jumlah kontainer 20ft yang masih bisa masuk, 0 untuk rencana 40ft
+	Remaining40ft int     `json:"remaining_40ft"` // Jumlah kontainer 40ft yang masih bisa masuk, 0 untuk rencana 20ft
+	FillPercent   float64 `json:"fill_percent"`   // Persentase cell terisi
+}
+
+type SpecCapacity struct {
+	Size         int     `json:"size"`
+	Height       float64 `json:"height"`
+	Type         string  `json:"type"`
+	FreePosition int     `json:"free_position"`
+}
+
+type BlockStatistics struct {
+	Block       string         `json:"block"`
+	TEUCapacity int            `json:"teu_capacity"`
+	TEUOccupied int            `json:"teu_occupied"`
+	Utilization float64        `json:"utilization"` // Persentase
+	Plans       []PlanCapacity `json:"plans"`
+}
+
+type YardStatistics struct {
+	Yard        string            `json:"yard"`
+	TEUCapacity int               `json:"teu_capacity"`
+	TEUOccupied int               `json:"teu_occupied"`
+	Utilization float64           `json:"utilization"` // Persentase
+	Blocks      []BlockStatistics `json:"blocks"`
+	Specs       []SpecCapacity    `json:"specs"`
+}
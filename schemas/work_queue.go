@@ -0,0 +1,28 @@
+package schemas
+
+type CreateEquipmentRequest struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Kind  string `json:"kind"`
+	Yard  string `json:"yard"`
+	Block string `json:"block"`
+}
+
+type ShiftContainerRequest struct {
+	Yard            string `json:"yard"`
+	ContainerNumber string `json:"container_number"`
+	Block           string `json:"block"`
+	Slot            int    `json:"slot"`
+	Row             int    `json:"row"`
+	Tier            int    `json:"tier"`
+}
+
+type FailJobRequest struct {
+	Reason string `json:"reason"`
+}
+
+type ListJobsRequest struct {
+	Yard      string `query:"yard"`
+	Equipment string `query:"equipment"`
+	Status    string `query:"status"`
+}
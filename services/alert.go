@@ -0,0 +1,215 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+	"yard-calculation/logging"
+	"yard-calculation/repositories"
+	"yard-calculation/schemas"
+)
+
+// Notifier adalah hook untuk meneruskan peringatan rencana yang hampir penuh
+type Notifier interface {
+	Notify(ctx context.Context, alert schemas.PlanSaturationAlert) error
+}
+
+// LogNotifier menulis peringatan ke log aplikasi
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, alert schemas.PlanSaturationAlert) error {
+	slog.WarnContext(ctx, "plan almost full",
+		logging.KeyYard, alert.Yard, logging.KeyBlock, alert.Plan.BlockID, "plan_id", alert.Plan.PlanID,
+		"fill_percent", alert.Plan.FillPercent, "threshold", alert.Threshold,
+		"remaining_20ft", alert.Plan.Remaining20ft, "remaining_40ft", alert.Plan.Remaining40ft)
+	return nil
+}
+
+// WebhookNotifier mengirim peringatan sebagai JSON ke URL tertentu
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{URL: url, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, alert schemas.PlanSaturationAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s responded with status %d", n.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+type CapacityAlertService struct {
+	Repo       repositories.Store
+	Statistics *StatisticsService
+	Threshold  float64
+	Notifier   Notifier
+
+	mu sync.Mutex
+	// Rencana yang sudah dikirim peringatannya, supaya tidak dikirim ulang di setiap pengecekan
+	alerted map[uint]bool
+	// Rencana yang peringatannya sedang dikirim, supaya pengecekan bersamaan tidak mengirim dua kali
+	sending map[uint]bool
+	// Run dan pengiriman peringatan di background, ditunggu Wait saat shutdown
+	wg sync.WaitGroup
+}
+
+func NewCapacityAlertService(repo repositories.Store, statistics *StatisticsService, threshold float64, notifier Notifier) *CapacityAlertService {
+	return &CapacityAlertService{
+		Repo:       repo,
+		Statistics: statistics,
+		Threshold:  threshold,
+		Notifier:   notifier,
+		alerted:    make(map[uint]bool),
+		sending:    make(map[uint]bool),
+	}
+}
+
+// CheckPlanCapacity menghitung sisa kapasitas setiap rencana di semua yard dan mengirim peringatan
+// untuk rencana yang baru melewati threshold service. threshold <= 0 berarti laporan memakai threshold service.
+// Peringatan dikirim di background setelah laporan selesai, sehingga webhook yang lambat tidak menahan request
+// dan pengecekan lain.
+func (s *CapacityAlertService) CheckPlanCapacity(ctx context.Context, threshold float64) (*schemas.PlanCapacityReport, error) {
+	if threshold <= 0 {
+		threshold = s.Threshold
+	}
+
+	yards, err := s.Repo.GetAllYards(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := schemas.PlanCapacityReport{
+		Threshold: threshold,
+		CheckedAt: time.Now(),
+		Plans:     []schemas.PlanCapacity{},
+		Saturated: []schemas.PlanCapacity{},
+	}
+
+	candidates := []schemas.PlanSaturationAlert{}
+	for _, yard := range yards {
+		for _, block := range yard.Blocks {
+			if err := s.Repo.LoadBlockOccupancy(ctx, &block); err != nil {
+				return nil, fmt.Errorf("error loading occupancy for block %s: %v", block.ID, err)
+			}
+
+			for _, plan := range block.Plans {
+				capacity := s.Statistics.PlanCapacity(&block, plan)
+				report.Plans = append(report.Plans, capacity)
+
+				if capacity.FillPercent >= threshold {
+					report.Saturated = append(report.Saturated, capacity)
+				}
+
+				candidates = append(candidates, schemas.PlanSaturationAlert{
+					Yard:      yard.ID,
+					Threshold: s.Threshold,
+					Plan:      capacity,
+					CheckedAt: report.CheckedAt,
+				})
+			}
+		}
+	}
+
+	if alerts := s.takeAlerts(candidates); len(alerts) > 0 {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.sendAlerts(context.WithoutCancel(ctx), alerts)
+		}()
+	}
+	return &report, nil
+}
+
+// takeAlerts memilih peringatan yang perlu dikirim dan menandainya sedang dikirim. Notifikasi selalu memakai
+// threshold service, threshold request hanya untuk laporan.
+func (s *CapacityAlertService) takeAlerts(candidates []schemas.PlanSaturationAlert) []schemas.PlanSaturationAlert {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	alerts := []schemas.PlanSaturationAlert{}
+	for _, alert := range candidates {
+		planID := alert.Plan.PlanID
+		if alert.Plan.FillPercent < s.Threshold {
+			delete(s.alerted, planID)
+			continue
+		}
+		if s.alerted[planID] || s.sending[planID] || s.Notifier == nil {
+			continue
+		}
+		s.sending[planID] = true
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
+
+// sendAlerts mengirim peringatan tanpa memegang lock. Rencana ditandai sudah diperingatkan hanya jika pengiriman
+// berhasil, sehingga pengecekan berikutnya mencoba lagi.
+func (s *CapacityAlertService) sendAlerts(ctx context.Context, alerts []schemas.PlanSaturationAlert) {
+	for _, alert := range alerts {
+		err := s.Notifier.Notify(ctx, alert)
+		if err != nil {
+			slog.ErrorContext(ctx, "error sending saturation alert", logging.KeyYard, alert.Yard, "plan_id", alert.Plan.PlanID, "error", err)
+		}
+
+		s.mu.Lock()
+		delete(s.sending, alert.Plan.PlanID)
+		if err == nil {
+			s.alerted[alert.Plan.PlanID] = true
+		}
+		s.mu.Unlock()
+	}
+}
+
+// Start menjalankan Run di background
+func (s *CapacityAlertService) Start(ctx context.Context, interval time.Duration) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.Run(ctx, interval)
+	}()
+}
+
+// Wait menunggu Run yang dijalankan Start dan peringatan yang masih dikirim selesai. Dipanggil saat shutdown setelah
+// context Run dibatalkan dan server berhenti menerima request, sebelum database ditutup.
+func (s *CapacityAlertService) Wait() {
+	s.wg.Wait()
+}
+
+// Run menjalankan CheckPlanCapacity secara berkala sampai ctx dibatalkan
+func (s *CapacityAlertService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := s.CheckPlanCapacity(ctx, 0); err != nil {
+				slog.ErrorContext(ctx, "error checking plan capacity", "error", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+	"yard-calculation/models"
+	"yard-calculation/schemas"
+)
+
+// slowNotifier mencatat peringatan setelah jeda, seperti webhook yang lambat
+type slowNotifier struct {
+	mu   sync.Mutex
+	sent []uint
+}
+
+func (n *slowNotifier) Notify(_ context.Context, alert schemas.PlanSaturationAlert) error {
+	time.Sleep(50 * time.Millisecond)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, alert.Plan.PlanID)
+	return nil
+}
+
+func TestCapacityAlertWait(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestContainerService(t)
+	if err := service.PlaceContainerDetailed(ctx, "YRD1", "MSKU0000001", "A", 1, 1, 1, 20, 8.6, "DRY", "MSK", models.CargoStatusFull); err != nil {
+		t.Fatal(err)
+	}
+
+	notifier := &slowNotifier{}
+	alerts := NewCapacityAlertService(service.Repo, NewStatisticsService(service.Repo), 5, notifier)
+	if _, err := alerts.CheckPlanCapacity(ctx, 0); err != nil {
+		t.Fatal(err)
+	}
+	// Peringatan dikirim di background, Wait harus menunggu sampai selesai
+	alerts.Wait()
+
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	if len(notifier.sent) != 1 {
+		t.Fatalf("sent %d alert(s) after Wait, want 1", len(notifier.sent))
+	}
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"yard-calculation/models"
+	"yard-calculation/repositories"
+	"yard-calculation/schemas"
+)
+
+// AuditService menyimpan dan menampilkan audit log request yang mengubah data (auth.AuditRecorder)
+type AuditService struct {
+	Repo *repositories.AuditRepository
+}
+
+func NewAuditService(repo *repositories.AuditRepository) *AuditService {
+	return &AuditService{Repo: repo}
+}
+
+// RecordAudit memenuhi auth.AuditRecorder
+func (s *AuditService) RecordAudit(ctx context.Context, entry *models.AuditEntry) error {
+	return s.Repo.CreateAuditEntry(ctx, entry)
+}
+
+func (s *AuditService) ListAuditEntries(ctx context.Context, req schemas.ListAuditRequest) ([]models.AuditEntry, error) {
+	if req.Limit < 1 {
+		req.Limit = 100
+	}
+	if req.Limit > 1000 {
+		req.Limit = 1000
+	}
+
+	filter := repositories.AuditFilter{
+		User:            req.User,
+		Yard:            req.Yard,
+		ContainerNumber: req.ContainerNumber,
+		Limit:           req.Limit,
+	}
+	if req.From != "" {
+		from, err := time.ParseInLocation(time.DateOnly, req.From, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from date %s, expected format YYYY-MM-DD", req.From)
+		}
+		filter.From = &from
+	}
+	if req.To != "" {
+		to, err := time.ParseInLocation(time.DateOnly, req.To, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to date %s, expected format YYYY-MM-DD", req.To)
+		}
+		until := to.AddDate(0, 0, 1)
+		filter.Until = &until
+	}
+	return s.Repo.ListAuditEntries(ctx, filter)
+}
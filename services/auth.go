@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"yard-calculation/auth"
+	"yard-calculation/models"
+	"yard-calculation/repositories"
+)
+
+// lastUsedInterval membatasi penulisan last_used_at supaya request beruntun dari gate system tidak selalu menulis
+// ke database
+const lastUsedInterval = time.Minute
+
+// AuthService mengelola API key untuk client mesin
+type AuthService struct {
+	Repo *repositories.APIKeyRepository
+}
+
+func NewAuthService(repo *repositories.APIKeyRepository) *AuthService {
+	return &AuthService{Repo: repo}
+}
+
+// CreateAPIKey membuat kunci baru dengan role dan yard pemiliknya lalu mengembalikan kunci asli, yang tidak bisa
+// ditampilkan lagi setelah ini
+func (s *AuthService) CreateAPIKey(ctx context.Context, name, role string, yards []string) (string, *models.APIKey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", nil, fmt.Errorf("api key name is required")
+	}
+	if !auth.ValidRole(role) {
+		return "", nil, fmt.Errorf("invalid role %q, expected %s", role, strings.Join(auth.Roles(), ", "))
+	}
+	if len(yards) == 0 {
+		return "", nil, fmt.Errorf("at least one yard is required, use %s for all yards", auth.AllYards)
+	}
+	if _, err := s.Repo.GetAPIKeyByName(ctx, name); err == nil {
+		return "", nil, fmt.Errorf("api key %s already exists", name)
+	} else if err.Error() != fmt.Sprintf("api key %s not found", name) {
+		return "", nil, err
+	}
+
+	key, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		return "", nil, err
+	}
+	apiKey := &models.APIKey{Name: name, Prefix: prefix, KeyHash: hash, Role: role, Yards: strings.Join(yards, ",")}
+	if err := s.Repo.CreateAPIKey(ctx, apiKey); err != nil {
+		return "", nil, err
+	}
+	return key, apiKey, nil
+}
+
+// RevokeAPIKey mencabut kunci, request berikutnya dengan kunci ini ditolak
+func (s *AuthService) RevokeAPIKey(ctx context.Context, name string) (*models.APIKey, error) {
+	apiKey, err := s.Repo.GetAPIKeyByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey.RevokedAt != nil {
+		return nil, fmt.Errorf("api key %s is already revoked", name)
+	}
+	now := time.Now()
+	apiKey.RevokedAt = &now
+	if err := s.Repo.UpdateAPIKey(ctx, apiKey); err != nil {
+		return nil, err
+	}
+	return apiKey, nil
+}
+
+func (s *AuthService) ListAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	return s.Repo.ListAPIKeys(ctx)
+}
+
+// AuthenticateAPIKey mencari pemilik kunci, auth.ErrInvalidAPIKey jika kunci tidak dikenal atau sudah dicabut
+func (s *AuthService) AuthenticateAPIKey(ctx context.Context, key string) (*auth.Principal, error) {
+	apiKey, err := s.Repo.GetAPIKeyByHash(ctx, auth.HashAPIKey(key))
+	if err != nil {
+		if err.Error() == "api key not found" {
+			return nil, auth.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+	if apiKey.RevokedAt != nil {
+		return nil, auth.ErrInvalidAPIKey
+	}
+
+	if apiKey.Role == "" {
+		// Kunci dari sebelum migrasi 0003 tetap terautentikasi, tapi tanpa role semua route menolaknya dengan 403
+		slog.WarnContext(ctx, "api key has no role, re-issue it with apikey create -role ... -yards ...", "api_key", apiKey.Name)
+	}
+
+	now := time.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= lastUsedInterval {
+		// Gagal mencatat waktu pemakaian tidak menggagalkan request
+		if err := s.Repo.TouchAPIKey(ctx, apiKey.ID, now); err != nil {
+			slog.WarnContext(ctx, "error recording api key usage", "api_key", apiKey.Name, "error", err)
+		}
+	}
+	return &auth.Principal{
+		Kind:  auth.KindAPIKey,
+		Name:  apiKey.Name,
+		Roles: auth.ParseList(apiKey.Role),
+		Yards: auth.ParseList(apiKey.Yards),
+	}, nil
+}
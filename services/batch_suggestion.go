@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"yard-calculation/metrics"
+	"yard-calculation/models"
+	"yard-calculation/schemas"
+)
+
+// SuggestBatch mengalokasikan posisi untuk banyak kontainer sekaligus. Occupancy dimuat sekali dan setiap
+// posisi yang dialokasikan langsung ditandai terisi, sehingga kontainer berikutnya tidak mendapat posisi yang
+// sama. Kontainer yang sudah punya posisi dipesan mendapat posisi tersebut seperti GetSuggestedPosition.
+// Kontainer dengan group yang sama diarahkan ke block yang sudah dipakai group tersebut lebih dulu.
+// Tidak ada data yang disimpan, hasilnya hanya rencana.
+func (s *ContainerService) SuggestBatch(ctx context.Context, yardName string, items []schemas.BatchSuggestItem) (*schemas.BatchSuggestResponse, error) {
+	yard, err := s.Repo.GetYardByName(ctx, yardName)
+	if err != nil {
+		return nil, err
+	}
+	for i := range yard.Blocks {
+		if err := s.Repo.LoadBlockOccupancy(ctx, &yard.Blocks[i]); err != nil {
+			return nil, fmt.Errorf("error loading occupancy for block %s: %v", yard.Blocks[i].ID, err)
+		}
+	}
+	// Semua posisi yang dipesan ditandai terisi, termasuk pesanan kontainer di batch ini: pemesannya langsung
+	// mendapat posisi tersebut, kontainer lain di batch tidak boleh memakainya
+	reserved, err := s.reservedPositions(ctx, yard.ID)
+	if err != nil {
+		return nil, err
+	}
+	reservations := make(map[string]*models.Container) // Key: nomor kontainer, Value: pesanan pertama
+	for i := range reserved {
+		if reservations[reserved[i].ContainerNumber] == nil {
+			reservations[reserved[i].ContainerNumber] = &reserved[i]
+		}
+		s.occupy(yard, &reserved[i])
+	}
+
+	response := schemas.BatchSuggestResponse{
+		Yard:    yard.ID,
+		Total:   len(items),
+		Results: make([]schemas.BatchSuggestResult, 0, len(items)),
+	}
+	seen := make(map[string]bool)
+	groupBlocks := make(map[string][]string) // Key: group, Value: block yang sudah dipakai group
+
+	for _, item := range items {
+		// Batch yang dibatalkan dihentikan seluruhnya, bukan dilaporkan sebagai kontainer tanpa posisi
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result := schemas.BatchSuggestResult{ContainerNumber: item.ContainerNumber, Group: item.Group}
+
+		var suggested *models.Container
+		reason, err := s.checkBatchItem(ctx, item, seen)
+		if err == nil {
+			if suggested = reservations[item.ContainerNumber]; suggested != nil {
+				if err = s.checkReservation(ctx, suggested, item.ContainerSize, item.ContainerHeight, item.ContainerType); err != nil {
+					reason = schemas.BatchReasonReservationMismatch
+				}
+			} else {
+				suggested, err = s.Repo.FindSuggestedPosition(ctx, yard, item.ContainerSize, item.ContainerHeight, item.ContainerType, groupBlocks[item.Group])
+				if err != nil {
+					reason = schemas.BatchReasonPlanFull
+					if !yardHasPlanForSpec(yard, item.ContainerSize, item.ContainerHeight, item.ContainerType) {
+						reason = schemas.BatchReasonNoPlan
+					}
+				} else {
+					s.occupy(yard, suggested)
+				}
+			}
+		}
+		if err == nil {
+			if item.Group != "" && !slices.Contains(groupBlocks[item.Group], suggested.BlockID) {
+				groupBlocks[item.Group] = append(groupBlocks[item.Group], suggested.BlockID)
+			}
+			result.SuggestedPosition = &schemas.SuggestContainerResponse{
+				Yard:  suggested.YardID,
+				Block: suggested.BlockID,
+				Slot:  suggested.Slot,
+				Row:   suggested.Row,
+				Tier:  suggested.Tier,
+			}
+		}
+
+		if err != nil {
+			result.Reason = reason
+			result.Error = err.Error()
+			response.Unallocated++
+			metrics.RecordSuggestion(yard.ID, metrics.SuggestionFailure, reasonOrError(reason))
+		} else {
+			response.Allocated++
+			reason = metrics.SuggestionReasonPlanned
+			if reservations[item.ContainerNumber] != nil {
+				reason = metrics.SuggestionReasonReserved
+			}
+			metrics.RecordSuggestion(yard.ID, metrics.SuggestionSuccess, reason)
+		}
+		response.Results = append(response.Results, result)
+	}
+	response.Complete = response.Unallocated == 0
+
+	return &response, nil
+}
+
+// checkBatchItem memvalidasi satu kontainer sebelum dicarikan posisi
+func (s *ContainerService) checkBatchItem(ctx context.Context, item schemas.BatchSuggestItem, seen map[string]bool) (string, error) {
+	if item.ContainerNumber == "" || (item.ContainerSize != 20 && item.ContainerSize != 40) {
+		return schemas.BatchReasonInvalidSpec, fmt.Errorf("container_number and valid size (20 or 40) are required")
+	}
+	if seen[item.ContainerNumber] {
+		return schemas.BatchReasonDuplicate, fmt.Errorf("container %s appears more than once in batch", item.ContainerNumber)
+	}
+	seen[item.ContainerNumber] = true
+
+	existing, err := s.Repo.GetContainerByNumber(ctx, item.ContainerNumber)
+	if err != nil && err.Error() != fmt.Sprintf("container with number %s not found or not placed", item.ContainerNumber) {
+		return "", err
+	}
+	if existing != nil {
+		return schemas.BatchReasonAlreadyPlaced, fmt.Errorf("container with number %s is already placed at %s-%d-%d-%d", item.ContainerNumber, existing.BlockID, existing.Slot, existing.Row, existing.Tier)
+	}
+	return "", nil
+}
+
+// reasonOrError mengisi alasan kosong (error repository saat validasi) dengan ERROR untuk label metric
+func reasonOrError(reason string) string {
+	if reason == "" {
+		return metrics.SuggestionReasonError
+	}
+	return reason
+}
+
+func (s *ContainerService) occupy(yard *models.Yard, c *models.Container) {
+	for i := range yard.Blocks {
+		if yard.Blocks[i].ID == c.BlockID {
+			s.Repo.OccupyPosition(&yard.Blocks[i], c.Slot, c.Row, c.Tier, c.Size)
+			return
+		}
+	}
+}
+
+func yardHasPlanForSpec(yard *models.Yard, size int, height float64, ctype string) bool {
+	for _, block := range yard.Blocks {
+		for _, p := range block.Plans {
+			if p.PlannedSize == size && p.PlannedHeight == height && p.PlannedType == ctype {
+				return true
+			}
+		}
+	}
+	return false
+}
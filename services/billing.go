@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"yard-calculation/models"
+	"yard-calculation/repositories"
+	"yard-calculation/schemas"
+)
+
+type BillingService struct {
+	Repo          *repositories.TariffRepository
+	ContainerRepo repositories.Store
+}
+
+func NewBillingService(repo *repositories.TariffRepository, containerRepo repositories.Store) *BillingService {
+	return &BillingService{Repo: repo, ContainerRepo: containerRepo}
+}
+
+func (s *BillingService) CreateTariff(ctx context.Context, req schemas.CreateTariffRequest) (*models.StorageTariff, error) {
+	tariff := &models.StorageTariff{
+		Name:        req.Name,
+		Size:        req.ContainerSize,
+		Type:        req.ContainerType,
+		CargoStatus: req.CargoStatus,
+		FreeDays:    req.FreeDays,
+		DailyRate:   req.DailyRate,
+	}
+	if err := s.Repo.CreateTariff(ctx, tariff); err != nil {
+		return nil, err
+	}
+	return tariff, nil
+}
+
+func (s *BillingService) ListTariffs(ctx context.Context) ([]models.StorageTariff, error) {
+	return s.Repo.GetAllTariffs(ctx)
+}
+
+func (s *BillingService) DeleteTariff(ctx context.Context, id uint) error {
+	return s.Repo.DeleteTariff(ctx, id)
+}
+
+// GetStorageCharges menghitung biaya penumpukan per kontainer dan per customer (shipping line) untuk rentang
+// tanggal. Hari dihitung per tanggal kalender termasuk hari masuk dan hari keluar; masa bebas dihitung dari hari
+// masuk, sehingga hanya hari ke-(FreeDays+1) dan seterusnya yang jatuh di dalam rentang yang ditagih.
+func (s *BillingService) GetStorageCharges(ctx context.Context, req schemas.StorageChargeRequest) (*schemas.StorageChargeResponse, error) {
+	from, err := time.ParseInLocation(time.DateOnly, req.From, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from date %s, expected format YYYY-MM-DD", req.From)
+	}
+	to, err := time.ParseInLocation(time.DateOnly, req.To, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to date %s, expected format YYYY-MM-DD", req.To)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("invalid date range: to %s is before from %s", req.To, req.From)
+	}
+
+	tariffs, err := s.Repo.GetAllTariffs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Tanggal akhir inklusif, jadi batas atas adalah awal hari berikutnya
+	visits, err := s.ContainerRepo.GetVisitsInRange(ctx, req.Yard, req.Customer, from, to.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, err
+	}
+
+	response := schemas.StorageChargeResponse{
+		Yard:       req.Yard,
+		From:       req.From,
+		To:         req.To,
+		Customers:  []schemas.CustomerCharge{},
+		Containers: []schemas.ContainerCharge{},
+	}
+	customerIndex := make(map[string]int)
+	today := startOfDay(time.Now())
+
+	for _, visit := range visits {
+		charge := storageCharge(visit, tariffs, from, to, today)
+		response.Containers = append(response.Containers, charge)
+		response.Total += charge.Amount
+
+		idx, ok := customerIndex[charge.Customer]
+		if !ok {
+			idx = len(response.Customers)
+			customerIndex[charge.Customer] = idx
+			response.Customers = append(response.Customers, schemas.CustomerCharge{Customer: charge.Customer})
+		}
+		response.Customers[idx].Containers++
+		response.Customers[idx].BillableDays += charge.BillableDays
+		response.Customers[idx].Amount += charge.Amount
+	}
+	return &response, nil
+}
+
+// storageCharge menghitung biaya satu kunjungan, from dan to adalah awal hari pertama dan terakhir rentang
+func storageCharge(visit models.Container, tariffs []models.StorageTariff, from, to, today time.Time) schemas.ContainerCharge {
+	placedAt := visit.CreatedAt
+	if visit.PlacedAt != nil {
+		placedAt = *visit.PlacedAt
+	}
+	// Data lama tanpa status muatan dianggap FULL, sama dengan default placement
+	if visit.CargoStatus == "" {
+		visit.CargoStatus = models.CargoStatusFull
+	}
+	pickedUpAt := visit.PickedUpAt
+	if pickedUpAt == nil && !visit.IsPlaced {
+		pickedUpAt = &visit.UpdatedAt
+	}
+
+	charge := schemas.ContainerCharge{
+		ContainerNumber: visit.ContainerNumber,
+		Customer:        visit.LineOperator,
+		Size:            visit.Size,
+		Type:            visit.Type,
+		CargoStatus:     visit.CargoStatus,
+		PlacedAt:        placedAt,
+		PickedUpAt:      pickedUpAt,
+	}
+
+	// Kontainer yang masih di lapangan dihitung sampai akhir rentang, tapi tidak melewati hari ini
+	firstDay := startOfDay(placedAt)
+	lastDay := to
+	if today.Before(lastDay) {
+		lastDay = today
+	}
+	if pickedUpAt != nil {
+		lastDay = startOfDay(*pickedUpAt)
+	}
+	if lastDay.Before(firstDay) {
+		lastDay = firstDay
+	}
+	charge.DwellDays = daysBetween(firstDay, lastDay) + 1
+
+	tariff := findTariff(tariffs, visit)
+	if tariff == nil {
+		charge.Error = fmt.Sprintf("no storage tariff for container spec (size: %d, type: %s, cargo_status: %s)", visit.Size, visit.Type, visit.CargoStatus)
+		return charge
+	}
+	charge.TariffID = tariff.ID
+	charge.FreeDays = tariff.FreeDays
+	charge.DailyRate = tariff.DailyRate
+
+	// Hari ke-n kunjungan (mulai dari 1) yang jatuh di dalam rentang dan sudah lewat masa bebas
+	firstBillable := max(daysBetween(firstDay, from)+1, tariff.FreeDays+1, 1)
+	lastBillable := min(daysBetween(firstDay, to)+1, charge.DwellDays)
+	if lastBillable >= firstBillable {
+		charge.BillableDays = lastBillable - firstBillable + 1
+	}
+	charge.Amount = float64(charge.BillableDays) * tariff.DailyRate
+	return charge
+}
+
+// findTariff memilih tarif yang cocok dengan jumlah kriteria terisi terbanyak, tarif pertama jika sama
+func findTariff(tariffs []models.StorageTariff, container models.Container) *models.StorageTariff {
+	var best *models.StorageTariff
+	bestScore := -1
+	for i, tariff := range tariffs {
+		score := 0
+		if tariff.Size != 0 {
+			if tariff.Size != container.Size {
+				continue
+			}
+			score++
+		}
+		if tariff.Type != "" {
+			if tariff.Type != container.Type {
+				continue
+			}
+			score++
+		}
+		if tariff.CargoStatus != "" {
+			if tariff.CargoStatus != container.CargoStatus {
+				continue
+			}
+			score++
+		}
+		if score > bestScore {
+			best = &tariffs[i]
+			bestScore = score
+		}
+	}
+	return best
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// daysBetween menghitung selisih hari kalender, dibulatkan supaya pergantian DST tidak menggeser hasil
+func daysBetween(a, b time.Time) int {
+	hours := b.Sub(a).Hours()
+	if hours < 0 {
+		return -int(-hours/24 + 0.5)
+	}
+	return int(hours/24 + 0.5)
+}
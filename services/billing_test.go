@@ -0,0 +1,184 @@
+package services
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+	"yard-calculation/models"
+)
+
+// withLocation mengganti time.Local selama test, supaya perhitungan hari kalender dan DST tidak bergantung pada
+// zona waktu mesin
+func withLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := time.Local
+	time.Local = loc
+	t.Cleanup(func() { time.Local = previous })
+	return loc
+}
+
+func TestStorageCharge(t *testing.T) {
+	// Europe/Amsterdam pindah ke waktu musim panas pada 31 Maret 2024, hari itu hanya 23 jam
+	loc := withLocation(t, "Europe/Amsterdam")
+	day := func(d string) time.Time {
+		t, err := time.ParseInLocation(time.DateOnly, d, loc)
+		if err != nil {
+			panic(err)
+		}
+		return t
+	}
+	at := func(d string, hour int) *time.Time {
+		t := day(d).Add(time.Duration(hour) * time.Hour)
+		return &t
+	}
+	tariffs := []models.StorageTariff{{ID: 1, Name: "DRY", Type: "DRY", FreeDays: 3, DailyRate: 10}}
+
+	tests := []struct {
+		name         string
+		visit        models.Container
+		from, to     string
+		today        string
+		wantDwell    int
+		wantBillable int
+		wantError    string
+	}{
+		{
+			name:  "visit starts before range and is still placed",
+			visit: models.Container{Type: "DRY", IsPlaced: true, PlacedAt: at("2024-03-01", 10)},
+			from:  "2024-03-10", to: "2024-03-15", today: "2024-06-01",
+			// Hari ke-10 sampai ke-15 kunjungan, semuanya sudah lewat masa bebas
+			wantDwell: 15, wantBillable: 6,
+		},
+		{
+			name:  "free days fall before range",
+			visit: models.Container{Type: "DRY", IsPlaced: true, PlacedAt: at("2024-03-09", 23)},
+			from:  "2024-03-10", to: "2024-03-15", today: "2024-06-01",
+			// Hari ke-1 (9 Maret) di luar rentang, hari ke-2 dan ke-3 bebas, 12-15 Maret ditagih
+			wantDwell: 7, wantBillable: 4,
+		},
+		{
+			name:  "picked up inside range",
+			visit: models.Container{Type: "DRY", PlacedAt: at("2024-03-08", 9), PickedUpAt: at("2024-03-12", 17)},
+			from:  "2024-03-10", to: "2024-03-15", today: "2024-06-01",
+			// 8-10 Maret bebas, 11 dan 12 Maret ditagih
+			wantDwell: 5, wantBillable: 2,
+		},
+		{
+			name:  "picked up before range",
+			visit: models.Container{Type: "DRY", PlacedAt: at("2024-03-01", 9), PickedUpAt: at("2024-03-09", 17)},
+			from:  "2024-03-10", to: "2024-03-15", today: "2024-06-01",
+			wantDwell: 9, wantBillable: 0,
+		},
+		{
+			name:  "longer than free days inside range",
+			visit: models.Container{Type: "DRY", PlacedAt: at("2024-03-10", 6), PickedUpAt: at("2024-03-14", 20)},
+			from:  "2024-03-01", to: "2024-03-31", today: "2024-06-01",
+			wantDwell: 5, wantBillable: 2,
+		},
+		{
+			name:  "within free days",
+			visit: models.Container{Type: "DRY", PlacedAt: at("2024-03-10", 6), PickedUpAt: at("2024-03-12", 20)},
+			from:  "2024-03-01", to: "2024-03-31", today: "2024-06-01",
+			wantDwell: 3, wantBillable: 0,
+		},
+		{
+			name:  "placed and picked up the same day",
+			visit: models.Container{Type: "DRY", PlacedAt: at("2024-03-10", 6), PickedUpAt: at("2024-03-10", 20)},
+			from:  "2024-03-01", to: "2024-03-31", today: "2024-06-01",
+			wantDwell: 1, wantBillable: 0,
+		},
+		{
+			name:  "still placed is counted until today",
+			visit: models.Container{Type: "DRY", IsPlaced: true, PlacedAt: at("2024-03-10", 6)},
+			from:  "2024-03-01", to: "2024-03-31", today: "2024-03-14",
+			wantDwell: 5, wantBillable: 2,
+		},
+		{
+			name:  "visit across DST start",
+			visit: models.Container{Type: "DRY", PlacedAt: at("2024-03-29", 8), PickedUpAt: at("2024-04-02", 8)},
+			from:  "2024-03-29", to: "2024-04-02", today: "2024-06-01",
+			// 29 Maret - 2 April adalah 5 hari kalender walaupun hanya 95 jam
+			wantDwell: 5, wantBillable: 2,
+		},
+		{
+			name:  "visit across DST end",
+			visit: models.Container{Type: "DRY", IsPlaced: true, PlacedAt: at("2024-10-25", 23)},
+			from:  "2024-10-28", to: "2024-10-31", today: "2024-12-01",
+			// Hari ke-4 (28 Oktober) sampai ke-7, 27 Oktober punya 25 jam
+			wantDwell: 7, wantBillable: 4,
+		},
+		{
+			name: "legacy visit without visit timestamps",
+			visit: models.Container{Type: "DRY", CreatedAt: day("2024-03-10").Add(6 * time.Hour),
+				UpdatedAt: day("2024-03-15").Add(6 * time.Hour)},
+			from: "2024-03-01", to: "2024-03-31", today: "2024-06-01",
+			wantDwell: 6, wantBillable: 3,
+		},
+		{
+			name:  "no tariff",
+			visit: models.Container{Type: "REEFER", Size: 20, IsPlaced: true, PlacedAt: at("2024-03-10", 6)},
+			from:  "2024-03-01", to: "2024-03-31", today: "2024-06-01",
+			wantDwell: 22, wantError: "no storage tariff for container spec (size: 20, type: REEFER, cargo_status: FULL)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			charge := storageCharge(tt.visit, tariffs, day(tt.from), day(tt.to), day(tt.today))
+			if charge.DwellDays != tt.wantDwell || charge.BillableDays != tt.wantBillable || charge.Error != tt.wantError {
+				t.Fatalf("storageCharge() = dwell %d, billable %d, error %q; want dwell %d, billable %d, error %q",
+					charge.DwellDays, charge.BillableDays, charge.Error, tt.wantDwell, tt.wantBillable, tt.wantError)
+			}
+			if want := float64(tt.wantBillable) * 10; tt.wantError == "" && charge.Amount != want {
+				t.Errorf("amount = %v, want %v", charge.Amount, want)
+			}
+		})
+	}
+}
+
+func TestFindTariff(t *testing.T) {
+	var (
+		all        = models.StorageTariff{ID: 1, Name: "all"}
+		size20     = models.StorageTariff{ID: 2, Name: "20ft", Size: 20}
+		reefer     = models.StorageTariff{ID: 3, Name: "reefer", Type: "REEFER"}
+		reefer20   = models.StorageTariff{ID: 4, Name: "20ft reefer", Size: 20, Type: "REEFER"}
+		empty      = models.StorageTariff{ID: 5, Name: "empty", CargoStatus: models.CargoStatusEmpty}
+		empty40    = models.StorageTariff{ID: 6, Name: "40ft empty", Size: 40, CargoStatus: models.CargoStatusEmpty}
+		dryEmpty   = models.StorageTariff{ID: 7, Name: "dry empty", Type: "DRY", CargoStatus: models.CargoStatusEmpty}
+		allTariffs = []models.StorageTariff{all, size20, reefer, reefer20, empty, empty40, dryEmpty}
+	)
+
+	tests := []struct {
+		name    string
+		tariffs []models.StorageTariff
+		size    int
+		ctype   string
+		status  string
+		want    uint
+	}{
+		{"only the generic tariff matches", allTariffs, 40, "DRY", models.CargoStatusFull, all.ID},
+		{"one criterion beats generic", allTariffs, 20, "DRY", models.CargoStatusFull, size20.ID},
+		{"two criteria beat one", allTariffs, 20, "REEFER", models.CargoStatusFull, reefer20.ID},
+		{"type only", allTariffs, 40, "REEFER", models.CargoStatusFull, reefer.ID},
+		{"two criteria beat several single matches", allTariffs, 20, "DRY", models.CargoStatusEmpty, dryEmpty.ID},
+		{"tie goes to the first tariff", allTariffs, 40, "DRY", models.CargoStatusEmpty, empty40.ID},
+		{"tie in reversed order", []models.StorageTariff{dryEmpty, empty40}, 40, "DRY", models.CargoStatusEmpty, dryEmpty.ID},
+		{"no match", []models.StorageTariff{size20, reefer}, 40, "DRY", models.CargoStatusFull, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got uint
+			if tariff := findTariff(tt.tariffs, models.Container{Size: tt.size, Type: tt.ctype, CargoStatus: tt.status}); tariff != nil {
+				got = tariff.ID
+			}
+			if got != tt.want {
+				t.Errorf("findTariff() = tariff %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"yard-calculation/repositories"
+	"yard-calculation/schemas"
+)
+
+type BlockService struct {
+	Repo repositories.Store
+}
+
+func NewBlockService(repo repositories.Store) *BlockService {
+	return &BlockService{Repo: repo}
+}
+
+func (s *BlockService) GetBlockMap(ctx context.Context, yardName, blockName string) (*schemas.BlockMapResponse, error) {
+	block, err := s.Repo.GetBlockByName(ctx, blockName, yardName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.Repo.LoadBlockOccupancy(ctx, block); err != nil {
+		return nil, fmt.Errorf("error loading block occupancy: %v", err)
+	}
+
+	plans, err := s.Repo.GetPlansForBlock(ctx, yardName, blockName)
+	if err != nil {
+		return nil, fmt.Errorf("error loading block plans: %v", err)
+	}
+
+	response := schemas.BlockMapResponse{
+		Yard:      yardName,
+		Block:     block.ID,
+		TotalSlot: block.TotalSlot,
+		TotalRow:  block.TotalRow,
+		TotalTier: block.TotalTier,
+		Cells:     make([][][]schemas.BlockMapCell, block.TotalSlot),
+	}
+
+	for slot := 1; slot <= block.TotalSlot; slot++ {
+		response.Cells[slot-1] = make([][]schemas.BlockMapCell, block.TotalRow)
+		for row := 1; row <= block.TotalRow; row++ {
+			response.Cells[slot-1][row-1] = make([]schemas.BlockMapCell, block.TotalTier)
+			for tier := 1; tier <= block.TotalTier; tier++ {
+				cell := schemas.BlockMapCell{Slot: slot, Row: row, Tier: tier, PlanIDs: []uint{}}
+
+				if c, ok := s.Repo.GetOccupant(block, slot, row, tier); ok {
+					cell.ContainerNumber = c.ContainerNumber
+					cell.ContainerSize = c.Size
+					cell.ContainerType = c.Type
+					cell.IsSecondHalf = c.Slot != slot
+				}
+
+				for _, p := range plans {
+					if slot >= p.MinSlot && slot <= p.MaxSlot &&
+						row >= p.MinRow && row <= p.MaxRow &&
+						tier >= p.MinTier && tier <= p.MaxTier {
+						cell.PlanIDs = append(cell.PlanIDs, p.ID)
+					}
+				}
+
+				response.Cells[slot-1][row-1][tier-1] = cell
+			}
+		}
+	}
+
+	return &response, nil
+}
+
+// RenderBlockMapText menampilkan block per row (section), tier dari atas ke bawah dan slot dari kiri ke kanan
+func RenderBlockMapText(m *schemas.BlockMapResponse) string {
+	const cellWidth = 12
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "YARD %s  BLOCK %s  (%d slot x %d row x %d tier)\n", m.Yard, m.Block, m.TotalSlot, m.TotalRow, m.TotalTier)
+
+	for row := 1; row <= m.TotalRow; row++ {
+		fmt.Fprintf(&b, "\nROW %02d\n", row)
+		for tier := m.TotalTier; tier >= 1; tier-- {
+			fmt.Fprintf(&b, "T%02d |", tier)
+			for slot := 1; slot <= m.TotalSlot; slot++ {
+				cell := m.Cells[slot-1][row-1][tier-1]
+				label := "."
+				if cell.IsSecondHalf {
+					label = "<<"
+				} else if cell.ContainerNumber != "" {
+					label = cell.ContainerNumber
+				}
+				fmt.Fprintf(&b, " %-*s", cellWidth, label)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("    |")
+		for slot := 1; slot <= m.TotalSlot; slot++ {
+			fmt.Fprintf(&b, " %-*s", cellWidth, fmt.Sprintf("S%02d", slot))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n. = kosong, << = bagian kedua kontainer 40ft\n")
+	return b.String()
+}
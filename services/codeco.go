@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"yard-calculation/edifact"
+	"yard-calculation/models"
+	"yard-calculation/repositories"
+	"yard-calculation/schemas"
+)
+
+type CodecoService struct {
+	ContainerService *ContainerService
+	WorkQueue        *WorkQueueService
+	PreAdviceRepo    *repositories.PreAdviceRepository
+}
+
+func NewCodecoService(containerService *ContainerService, workQueue *WorkQueueService, preAdviceRepo *repositories.PreAdviceRepository) *CodecoService {
+	return &CodecoService{ContainerService: containerService, WorkQueue: workQueue, PreAdviceRepo: preAdviceRepo}
+}
+
+// IngestCodeco memproses file CODECO: gate-in dicatat sebagai kedatangan (pre-advice ARRIVED),
+// gate-out dijalankan sebagai pickup. Event yang gagal dilaporkan per segment, event lain tetap diproses.
+func (s *CodecoService) IngestCodeco(ctx context.Context, yardName, data string) (*schemas.EDIIngestResponse, error) {
+	interchange, err := edifact.ParseInterchange(data)
+	if err != nil {
+		return nil, err
+	}
+
+	response := schemas.EDIIngestResponse{Errors: []edifact.SegmentError{}}
+	for _, msg := range interchange.Messages {
+		response.Messages++
+
+		events, errs := edifact.ParseCodeco(msg)
+		response.Errors = append(response.Errors, errs...)
+
+		for _, event := range events {
+			if event.GateIn {
+				err = s.applyGateIn(ctx, yardName, event)
+			} else {
+				err = s.applyGateOut(ctx, yardName, event)
+			}
+			if err != nil {
+				response.Errors = append(response.Errors, edifact.SegmentError{
+					MessageRef:      event.MessageRef,
+					Segment:         event.Segment,
+					Tag:             "EQD",
+					ContainerNumber: event.ContainerNumber,
+					Message:         err.Error(),
+				})
+				continue
+			}
+			if event.GateIn {
+				response.Arrivals++
+			} else {
+				response.Pickups++
+			}
+		}
+	}
+	return &response, nil
+}
+
+// applyGateOut menjalankan pickup seperti POST /pickup, tapi ditolak selama kontainer masih punya job terbuka
+// atau posisi di atasnya menjadi tujuan job, supaya job tersebut tidak menunjuk kontainer atau tumpukan yang sudah
+// tidak ada
+func (s *CodecoService) applyGateOut(ctx context.Context, yardName string, event edifact.CodecoEvent) error {
+	container, err := s.ContainerService.ValidatePickup(ctx, yardName, event.ContainerNumber)
+	if err != nil {
+		return err
+	}
+	if s.WorkQueue != nil {
+		if err := s.WorkQueue.CheckGateOut(ctx, container); err != nil {
+			return err
+		}
+	}
+	return s.ContainerService.CommitPickup(ctx, container)
+}
+
+func (s *CodecoService) applyGateIn(ctx context.Context, yardName string, event edifact.CodecoEvent) error {
+	sizeType, err := edifact.ParseSizeType(event.SizeType)
+	if err != nil {
+		return err
+	}
+
+	arrivedAt := event.EventTime
+	if arrivedAt.IsZero() {
+		arrivedAt = time.Now()
+	}
+
+	// Jika sudah ada pre-advice yang terbuka, tandai sebagai sudah tiba
+	preAdvice, err := s.PreAdviceRepo.GetOpenPreAdvice(ctx, yardName, event.ContainerNumber)
+	if err != nil && err.Error() != fmt.Sprintf("pre-advice for container %s in yard %s not found", event.ContainerNumber, yardName) {
+		return err
+	}
+	cargoStatus := models.CargoStatusEmpty
+	if event.Full {
+		cargoStatus = models.CargoStatusFull
+	}
+	if preAdvice != nil {
+		preAdvice.Status = models.PreAdviceStatusArrived
+		preAdvice.CargoStatus = cargoStatus
+		preAdvice.ArrivedAt = &arrivedAt
+		return s.PreAdviceRepo.UpdatePreAdvice(ctx, preAdvice)
+	}
+
+	return s.PreAdviceRepo.CreatePreAdvice(ctx, &models.PreAdvice{
+		ContainerNumber: event.ContainerNumber,
+		YardID:          yardName,
+		Size:            sizeType.Size,
+		Height:          sizeType.Height,
+		Type:            sizeType.Type,
+		SizeType:        sizeType.Code,
+		CargoStatus:     cargoStatus,
+		Status:          models.PreAdviceStatusArrived,
+		Source:          "CODECO",
+		MessageRef:      event.MessageRef,
+		ArrivedAt:       &arrivedAt,
+	})
+}
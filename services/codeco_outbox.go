@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+	"yard-calculation/config"
+	"yard-calculation/edifact"
+	"yard-calculation/logging"
+	"yard-calculation/models"
+	"yard-calculation/repositories"
+)
+
+// CodecoOutboxService menulis CODECO untuk setiap penempatan (gate-in) dan pickup (gate-out) ke outbox
+// shipping line pemilik kontainer. Kontainer tanpa shipping line atau dengan shipping line yang tidak
+// terdaftar di partner dilewati.
+type CodecoOutboxService struct {
+	Repo          *repositories.EDIOutboxRepository
+	ContainerRepo repositories.Store
+	Config        config.EDIConfig
+}
+
+func NewCodecoOutboxService(repo *repositories.EDIOutboxRepository, containerRepo repositories.Store, cfg config.EDIConfig) *CodecoOutboxService {
+	return &CodecoOutboxService{Repo: repo, ContainerRepo: containerRepo, Config: cfg}
+}
+
+// HandleContainerEvent memenuhi ContainerEventListener
+func (s *CodecoOutboxService) HandleContainerEvent(ctx context.Context, eventType string, container *models.Container) {
+	// Shift di dalam yard tidak dilaporkan ke shipping line
+	if eventType != ContainerEventPlaced && eventType != ContainerEventPickedUp {
+		return
+	}
+	if _, err := s.SendCodeco(ctx, eventType == ContainerEventPlaced, container); err != nil {
+		slog.ErrorContext(ctx, "error writing CODECO", "error", err)
+	}
+}
+
+// SendCodeco menulis satu CODECO, mengembalikan false jika dilewati atau sudah pernah dikirim
+func (s *CodecoOutboxService) SendCodeco(ctx context.Context, gateIn bool, container *models.Container) (bool, error) {
+	partner, ok := s.Config.Partners[container.LineOperator]
+	if !ok {
+		return false, nil
+	}
+
+	// Waktu gate-in dan gate-out diambil dari kunjungan, baris lama tanpa placed_at/picked_up_at memakai
+	// created_at/updated_at seperti perhitungan tarif penumpukan
+	direction, eventTime := "OUT", container.UpdatedAt
+	if container.PickedUpAt != nil {
+		eventTime = *container.PickedUpAt
+	}
+	if gateIn {
+		direction, eventTime = "IN", container.CreatedAt
+		if container.PlacedAt != nil {
+			eventTime = *container.PlacedAt
+		}
+	}
+	if eventTime.IsZero() {
+		eventTime = time.Now()
+	}
+	eventKey := fmt.Sprintf("CODECO-%s-%d", direction, container.ID)
+
+	sent, err := s.Repo.IsSent(ctx, eventKey)
+	if err != nil || sent {
+		return false, err
+	}
+
+	sizeType, err := edifact.FormatSizeType(container.Size, container.Height, container.Type)
+	if err != nil {
+		return false, err
+	}
+
+	message := &models.EDIOutboxMessage{
+		EventKey:        eventKey,
+		MessageType:     "CODECO",
+		LineOperator:    partner.LineOperator,
+		ContainerNumber: container.ContainerNumber,
+	}
+	// File ditulis dengan akhiran .tmp di dalam transaksi dan baru diganti ke nama akhirnya setelah commit, sehingga
+	// transaksi yang gagal tidak meninggalkan file di outbox dan replay tidak menghasilkan file kedua
+	var tmpName string
+	err = s.Repo.RecordMessage(ctx, message, func(message *models.EDIOutboxMessage) error {
+		reference := fmt.Sprintf("%d", message.ID)
+		content := edifact.BuildCodeco(edifact.CodecoParams{
+			Sender:          partner.Sender,
+			Recipient:       partner.Recipient,
+			Reference:       reference,
+			GateIn:          gateIn,
+			ContainerNumber: container.ContainerNumber,
+			SizeType:        sizeType,
+			Full:            container.CargoStatus != models.CargoStatusEmpty,
+			Location:        container.YardID,
+			EventTime:       eventTime,
+		})
+
+		dir := filepath.Join(s.Config.OutboxDir, partner.LineOperator)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+		message.FileName = filepath.Join(dir, fmt.Sprintf("CODECO_%s_%s_%s.edi", direction, container.ContainerNumber, reference))
+		tmpName = message.FileName + ".tmp"
+		return os.WriteFile(tmpName, []byte(content), 0o644)
+	})
+	if err != nil {
+		if tmpName != "" {
+			os.Remove(tmpName)
+		}
+		return false, err
+	}
+	if err := os.Rename(tmpName, message.FileName); err != nil {
+		return false, fmt.Errorf("CODECO %s is recorded but could not be published, move %s to %s manually: %v", eventKey, tmpName, message.FileName, err)
+	}
+	return true, nil
+}
+
+// Replay menulis ulang CODECO untuk kontainer yang ditempatkan atau diambil sejak waktu tertentu, event yang
+// sudah pernah dikirim dilewati sehingga aman dijalankan berulang kali
+func (s *CodecoOutboxService) Replay(ctx context.Context, since time.Time) (int, error) {
+	written := 0
+	var sendErr error
+	err := s.ContainerRepo.StreamContainers(ctx, repositories.ContainerFilter{UpdatedFrom: &since}, func(c *models.Container) error {
+		ok, err := s.SendCodeco(ctx, true, c)
+		if err != nil {
+			sendErr = err
+			slog.ErrorContext(logging.With(ctx, logging.KeyContainer, c.ContainerNumber, logging.KeyYard, c.YardID), "error writing CODECO gate-in", "error", err)
+		} else if ok {
+			written++
+		}
+		if !c.IsPlaced {
+			ok, err := s.SendCodeco(ctx, false, c)
+			if err != nil {
+				sendErr = err
+				slog.ErrorContext(logging.With(ctx, logging.KeyContainer, c.ContainerNumber, logging.KeyYard, c.YardID), "error writing CODECO gate-out", "error", err)
+			} else if ok {
+				written++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return written, err
+	}
+	return written, sendErr
+}
@@ -1,37 +1,209 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"time"
+	"yard-calculation/logging"
+	"yard-calculation/metrics"
 	"yard-calculation/models"
 	"yard-calculation/repositories"
 	"yard-calculation/schemas"
 )
 
+// Jenis event kontainer yang diteruskan ke ContainerEventListener
+const (
+	ContainerEventPlaced   = "PLACED"
+	ContainerEventPickedUp = "PICKED_UP"
+	ContainerEventShifted  = "SHIFTED"
+)
+
+// ContainerEventListener dipanggil setelah penempatan, pickup atau shift berhasil disimpan
+type ContainerEventListener interface {
+	HandleContainerEvent(ctx context.Context, eventType string, container *models.Container)
+}
+
+// PositionReserver memberikan posisi yang sudah dipesan tapi belum tersimpan di tabel containers (contoh: job
+// penempatan yang belum dikonfirmasi). Posisi ini dianggap terisi saat mencari dan memvalidasi posisi, kecuali
+// untuk kontainer yang memesannya sendiri.
+type PositionReserver interface {
+	ReservedPositions(ctx context.Context, yardID string) ([]models.Container, error)
+}
+
+// PlacementValidator menambah aturan penempatan di luar rencana yard, contoh: posisi harus sesuai pre-advice
+type PlacementValidator interface {
+	CheckPlacement(ctx context.Context, container *models.Container) error
+}
+
+// PlacementDefaulter mengisi data kontainer yang tidak dikirim di request penempatan, contoh: status full/empty yang
+// dilaporkan gate saat kontainer tiba
+type PlacementDefaulter interface {
+	ApplyPlacementDefaults(ctx context.Context, container *models.Container) error
+}
+
+// PickupValidator dapat menolak pickup kontainer, contoh: kontainer masih ditahan bea cukai
+type PickupValidator interface {
+	CheckPickup(ctx context.Context, container *models.Container) error
+}
+
 type ContainerService struct {
-	Repo *repositories.ContainerRepository
+	Repo             repositories.Store
+	Listeners        []ContainerEventListener
+	Reservers        []PositionReserver
+	Validators       []PlacementValidator
+	Defaulters       []PlacementDefaulter
+	PickupValidators []PickupValidator
 }
 
-func NewContainerService(repo *repositories.ContainerRepository) *ContainerService {
+func NewContainerService(repo repositories.Store) *ContainerService {
 	return &ContainerService{Repo: repo}
 }
 
-func (s *ContainerService) GetSuggestedPosition(yardName, containerNumber string, size int, height float64, ctype string) (*schemas.SuggestContainerResponse, error) {
+func (s *ContainerService) AddListener(listener ContainerEventListener) {
+	s.Listeners = append(s.Listeners, listener)
+}
+
+// publish dipanggil setelah perubahan tersimpan, sehingga listener (contoh: CODECO keluar) tetap dijalankan walaupun
+// deadline request lewat di tengah jalan
+func (s *ContainerService) publish(ctx context.Context, eventType string, container *models.Container) {
+	ctx = context.WithoutCancel(ctx)
+	for _, listener := range s.Listeners {
+		listener.HandleContainerEvent(ctx, eventType, container)
+	}
+}
+
+func (s *ContainerService) AddReserver(reserver PositionReserver) {
+	s.Reservers = append(s.Reservers, reserver)
+}
+
+func (s *ContainerService) AddValidator(validator PlacementValidator) {
+	s.Validators = append(s.Validators, validator)
+}
+
+func (s *ContainerService) AddDefaulter(defaulter PlacementDefaulter) {
+	s.Defaulters = append(s.Defaulters, defaulter)
+}
+
+func (s *ContainerService) AddPickupValidator(validator PickupValidator) {
+	s.PickupValidators = append(s.PickupValidators, validator)
+}
+
+// applyReservations menandai posisi yang dipesan di block sebagai terisi, Occupancy block harus sudah dimuat.
+// Pesanan milik ownNumber tidak ditandai tapi dikembalikan (nil jika tidak ada).
+func (s *ContainerService) applyReservations(ctx context.Context, yardID, ownNumber string, blocks ...*models.Block) (*models.Container, error) {
+	reserved, err := s.reservedPositions(ctx, yardID)
+	if err != nil {
+		return nil, err
+	}
+	var own *models.Container
+	for i, c := range reserved {
+		if c.ContainerNumber == ownNumber {
+			if own == nil {
+				own = &reserved[i]
+			}
+			continue
+		}
+		for _, block := range blocks {
+			if block.ID == c.BlockID {
+				s.Repo.OccupyPosition(block, c.Slot, c.Row, c.Tier, c.Size)
+			}
+		}
+	}
+	return own, nil
+}
+
+// reservedPositions menggabungkan posisi yang dipesan dari semua reserver, urut sesuai urutan reserver
+func (s *ContainerService) reservedPositions(ctx context.Context, yardID string) ([]models.Container, error) {
+	var all []models.Container
+	for _, reserver := range s.Reservers {
+		reserved, err := reserver.ReservedPositions(ctx, yardID)
+		if err != nil {
+			return nil, fmt.Errorf("error loading reserved positions: %v", err)
+		}
+		all = append(all, reserved...)
+	}
+	return all, nil
+}
+
+// loadYardOccupancy memuat occupancy semua block beserta posisi yang dipesan dan mengembalikan pesanan milik
+// ownNumber. Tanpa reserver, occupancy dibiarkan dimuat oleh FindSuggestedPosition hanya untuk block yang punya
+// rencana sesuai.
+func (s *ContainerService) loadYardOccupancy(ctx context.Context, yard *models.Yard, ownNumber string) (*models.Container, error) {
+	if len(s.Reservers) == 0 {
+		return nil, nil
+	}
+	blocks := make([]*models.Block, len(yard.Blocks))
+	for i := range yard.Blocks {
+		if err := s.Repo.LoadBlockOccupancy(ctx, &yard.Blocks[i]); err != nil {
+			return nil, fmt.Errorf("error loading occupancy for block %s: %v", yard.Blocks[i].ID, err)
+		}
+		blocks[i] = &yard.Blocks[i]
+	}
+	return s.applyReservations(ctx, yard.ID, ownNumber, blocks...)
+}
+
+func (s *ContainerService) GetSuggestedPosition(ctx context.Context, yardName, containerNumber string, size int, height float64, ctype string) (response *schemas.SuggestContainerResponse, err error) {
+	ctx = logging.With(ctx, logging.KeyContainer, containerNumber, logging.KeyYard, yardName)
+
+	// Hasil dicatat di metric yard_suggestions_total, yard hanya diisi jika yard ditemukan
+	yardLabel, reason := "", metrics.SuggestionReasonError
+	defer func() {
+		result := metrics.SuggestionSuccess
+		if err != nil {
+			result = metrics.SuggestionFailure
+			if ctx.Err() != nil {
+				reason = metrics.SuggestionReasonCancelled
+			}
+			slog.WarnContext(ctx, "no position suggested", "reason", reason, "error", err)
+		} else {
+			slog.InfoContext(logging.With(ctx, logging.KeyBlock, response.Block), "position suggested", "reason", reason,
+				"slot", response.Slot, "row", response.Row, "tier", response.Tier)
+		}
+		metrics.RecordSuggestion(yardLabel, result, reason)
+	}()
+
 	// Ambil data yard dan blocks beserta plans
-	yard, err := s.Repo.GetYardByName(yardName)
+	yard, err := s.Repo.GetYardByName(ctx, yardName)
 	if err != nil {
+		if err.Error() == fmt.Sprintf("yard with name %s not found", yardName) {
+			reason = metrics.SuggestionReasonYardNotFound
+		}
 		return nil, err
 	}
+	yardLabel = yard.ID
 
-	// Cari posisi yang sesuai dengan rencana di *semua* block dalam yard
-	suggestedContainer, err := s.Repo.FindSuggestedPosition(yard, size, height, ctype)
+	reserved, err := s.loadYardOccupancy(ctx, yard, containerNumber)
 	if err != nil {
 		return nil, err
 	}
 
+	// Kontainer yang sudah punya posisi dipesan (pre-advice atau job) langsung diarahkan ke posisi tersebut, selama
+	// pesanan sesuai spesifikasi request dan rencana yard
+	if reserved != nil {
+		if err := s.checkReservation(ctx, reserved, size, height, ctype); err != nil {
+			return nil, err
+		}
+	}
+	suggestedContainer := reserved
+	reason = metrics.SuggestionReasonReserved
+	if suggestedContainer == nil {
+		// Cari posisi yang sesuai dengan rencana di *semua* block dalam yard
+		suggestedContainer, err = s.Repo.FindSuggestedPosition(ctx, yard, size, height, ctype, nil)
+		if err != nil {
+			reason = schemas.BatchReasonPlanFull
+			if !yardHasPlanForSpec(yard, size, height, ctype) {
+				reason = schemas.BatchReasonNoPlan
+			}
+			return nil, err
+		}
+		reason = metrics.SuggestionReasonPlanned
+	}
+
 	// Isi nomor kontainer ke hasil saran
 	suggestedContainer.ContainerNumber = containerNumber
 
-	response := schemas.SuggestContainerResponse{
+	response = &schemas.SuggestContainerResponse{
 		Yard:  suggestedContainer.YardID,
 		Block: suggestedContainer.BlockID,
 		Slot:  suggestedContainer.Slot,
@@ -39,76 +211,98 @@ func (s *ContainerService) GetSuggestedPosition(yardName, containerNumber string
 		Tier:  suggestedContainer.Tier,
 	}
 
-	return &response, nil
+	return response, nil
+}
+
+// checkReservation memastikan posisi yang dipesan untuk kontainer cocok dengan spesifikasi request dan masih
+// berada di rencana untuk spesifikasi tersebut. Pesanan yang tidak cocok tidak diganti posisi lain, karena
+// penempatan akan tetap ditolak oleh pemilik pesanan (contoh: PreAdviceService.CheckPlacement).
+func (s *ContainerService) checkReservation(ctx context.Context, reserved *models.Container, size int, height float64, ctype string) error {
+	if reserved.Size != size || reserved.Height != height || reserved.Type != ctype {
+		return fmt.Errorf("container %s is reserved at %s-%d-%d-%d for spec (size: %d, height: %.1f, type: %s), which does not match the requested spec (size: %d, height: %.1f, type: %s)",
+			reserved.ContainerNumber, reserved.BlockID, reserved.Slot, reserved.Row, reserved.Tier, reserved.Size, reserved.Height, reserved.Type, size, height, ctype)
+	}
+	plans, err := s.Repo.GetPlansForSpec(ctx, reserved.YardID, reserved.BlockID, size, height, ctype)
+	if err != nil {
+		return fmt.Errorf("error checking placement plan: %v", err)
+	}
+	if findCoveringPlan(plans, reserved.Slot, reserved.Row, reserved.Tier, size) == nil {
+		return fmt.Errorf("container %s is reserved at %s-%d-%d-%d, which is no longer in a planned area for container spec (size: %d, height: %.1f, type: %s)",
+			reserved.ContainerNumber, reserved.BlockID, reserved.Slot, reserved.Row, reserved.Tier, size, height, ctype)
+	}
+	return nil
 }
 
 // Ubah fungsi PlaceContainer untuk menerima informasi kontainer
-func (s *ContainerService) PlaceContainerDetailed(yardName, containerNumber, blockName string, slot, row, tier int, size int, height float64, ctype string) error {
-	block, err := s.Repo.GetBlockByName(blockName, yardName)
+func (s *ContainerService) PlaceContainerDetailed(ctx context.Context, yardName, containerNumber, blockName string, slot, row, tier int, size int, height float64, ctype, lineOperator, cargoStatus string) error {
+	containerToPlace, err := s.ValidatePlacement(ctx, yardName, containerNumber, blockName, slot, row, tier, size, height, ctype, lineOperator, cargoStatus)
 	if err != nil {
 		return err
 	}
+	return s.CommitPlacement(ctx, containerToPlace)
+}
+
+// ValidatePlacement menjalankan semua validasi penempatan dan mengembalikan kontainer yang siap disimpan
+func (s *ContainerService) ValidatePlacement(ctx context.Context, yardName, containerNumber, blockName string, slot, row, tier int, size int, height float64, ctype, lineOperator, cargoStatus string) (container *models.Container, err error) {
+	ctx = logging.With(ctx, logging.KeyContainer, containerNumber, logging.KeyYard, yardName, logging.KeyBlock, blockName)
+	defer func() {
+		if err != nil {
+			slog.WarnContext(ctx, "placement rejected", "slot", slot, "row", row, "tier", tier, "error", err)
+		}
+	}()
+
+	block, err := s.Repo.GetBlockByName(ctx, blockName, yardName)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := s.Repo.LoadBlockOccupancy(block); err != nil {
-		return fmt.Errorf("error loading block occupancy: %v", err)
+	if err := s.Repo.LoadBlockOccupancy(ctx, block); err != nil {
+		return nil, fmt.Errorf("error loading block occupancy: %v", err)
+	}
+	if _, err := s.applyReservations(ctx, yardName, containerNumber, block); err != nil {
+		return nil, err
 	}
 
 	// Validasi batas
 	if slot < 1 || slot > block.TotalSlot || row < 1 || row > block.TotalRow || tier < 1 || tier > block.TotalTier {
-		return fmt.Errorf("position out of bounds for block %s", blockName)
+		return nil, fmt.Errorf("position out of bounds for block %s", blockName)
 	}
 
 	// Cek apakah kontainer sudah ditempatkan
-	existingPlacedContainer, err := s.Repo.GetContainerByNumber(containerNumber)
+	existingPlacedContainer, err := s.Repo.GetContainerByNumber(ctx, containerNumber)
 	if err != nil && err.Error() != fmt.Sprintf("container with number %s not found or not placed", containerNumber) {
-		return err // Error lain
+		return nil, err // Error lain
 	}
 	if existingPlacedContainer != nil {
-		return fmt.Errorf("container with number %s is already placed at %s-%d-%d-%d", containerNumber, existingPlacedContainer.BlockID, existingPlacedContainer.Slot, existingPlacedContainer.Row, existingPlacedContainer.Tier)
+		return nil, fmt.Errorf("container with number %s is already placed at %s-%d-%d-%d", containerNumber, existingPlacedContainer.BlockID, existingPlacedContainer.Slot, existingPlacedContainer.Row, existingPlacedContainer.Tier)
 	}
 
 	// Validasi ketersediaan posisi berdasarkan ukuran
 	if size == 20 {
 		if !s.Repo.IsPositionAvailable(block, slot, row, tier) {
-			return fmt.Errorf("position %d-%d-%d in block %s is occupied", slot, row, tier, blockName)
+			return nil, fmt.Errorf("position %d-%d-%d in block %s is occupied", slot, row, tier, blockName)
 		}
 	} else if size == 40 {
 		if !s.Repo.IsPositionAvailable40ft(block, slot, row, tier) {
-			return fmt.Errorf("positions %d-%d-%d and %d-%d-%d in block %s are not available for 40ft container", slot, row, tier, slot+1, row, tier, blockName)
+			return nil, fmt.Errorf("positions %d-%d-%d and %d-%d-%d in block %s are not available for 40ft container", slot, row, tier, slot+1, row, tier, blockName)
 		}
 	} else {
-		return fmt.Errorf("unsupported container size: %d", size)
+		return nil, fmt.Errorf("unsupported container size: %d", size)
 	}
 
 	// --- Validasi Penempatan Sesuai Rencana ---
 	// Cek apakah ada rencana yang sesuai untuk spesifikasi kontainer di posisi yang dituju
-	plans, err := s.Repo.GetPlansForSpec(yardName, blockName, size, height, ctype)
+	plans, err := s.Repo.GetPlansForSpec(ctx, yardName, blockName, size, height, ctype)
 	if err != nil {
-		return fmt.Errorf("error checking placement plan: %v", err)
+		return nil, fmt.Errorf("error checking placement plan: %v", err)
 	}
 	if len(plans) == 0 {
-		return fmt.Errorf("no plan found for container spec (size: %d, height: %.1f, type: %s) at placement location in block %s", size, height, ctype, blockName)
+		return nil, fmt.Errorf("no plan found for container spec (size: %d, height: %.1f, type: %s) at placement location in block %s", size, height, ctype, blockName)
 	}
 
 	// Cek apakah posisi (slot, row, tier) atau (slot, slot+1, row, tier) masuk ke salah satu plan
-	validLocation := false
-	for _, p := range plans {
-		if tier >= p.MinTier && tier <= p.MaxTier &&
-			row >= p.MinRow && row <= p.MaxRow &&
-			slot >= p.MinSlot && slot <= p.MaxSlot {
-			if size == 40 { // Jika 40ft, cek slot+1 juga
-				if slot+1 >= p.MinSlot && slot+1 <= p.MaxSlot {
-					validLocation = true
-					break
-				}
-			} else { // Jika 20ft
-				validLocation = true
-				break
-			}
-		}
-	}
-	if !validLocation {
-		return fmt.Errorf("placement location (%d-%d-%d) does not match planned area for container spec (size: %d, height: %.1f, type: %s) in block %s", slot, row, tier, size, height, ctype, blockName)
+	if findCoveringPlan(plans, slot, row, tier, size) == nil {
+		return nil, fmt.Errorf("placement location (%d-%d-%d) does not match planned area for container spec (size: %d, height: %.1f, type: %s) in block %s", slot, row, tier, size, height, ctype, blockName)
 	}
 	// --- Akhir Validasi Penempatan Sesuai Rencana ---
 
@@ -118,6 +312,8 @@ func (s *ContainerService) PlaceContainerDetailed(yardName, containerNumber, blo
 		Size:            size,
 		Height:          height,
 		Type:            ctype,
+		LineOperator:    lineOperator,
+		CargoStatus:     cargoStatus,
 		YardID:          yardName,
 		BlockID:         blockName,
 		Slot:            slot,
@@ -127,23 +323,302 @@ func (s *ContainerService) PlaceContainerDetailed(yardName, containerNumber, blo
 		// YardPlanID:      nil, // Atau set ke ID plan jika diperlukan
 	}
 
-	return s.Repo.CreateContainer(containerToPlace)
+	// Status full/empty yang tidak dikirim request diambil dari gate-in, default FULL
+	for _, defaulter := range s.Defaulters {
+		if err := defaulter.ApplyPlacementDefaults(ctx, containerToPlace); err != nil {
+			return nil, err
+		}
+	}
+	if containerToPlace.CargoStatus == "" {
+		containerToPlace.CargoStatus = models.CargoStatusFull
+	}
+
+	for _, validator := range s.Validators {
+		if err := validator.CheckPlacement(ctx, containerToPlace); err != nil {
+			return nil, err
+		}
+	}
+
+	return containerToPlace, nil
 }
 
-func (s *ContainerService) PickupContainer(yardName, containerNumber string) error {
-	container, err := s.Repo.GetContainerByNumber(containerNumber)
+// CommitPlacement menyimpan kontainer hasil ValidatePlacement
+func (s *ContainerService) CommitPlacement(ctx context.Context, containerToPlace *models.Container) error {
+	ctx = containerLogContext(ctx, containerToPlace)
+	now := time.Now()
+	containerToPlace.PlacedAt = &now
+	if err := s.Repo.CreateContainer(ctx, containerToPlace); err != nil {
+		slog.ErrorContext(ctx, "error saving placement", "error", err)
+		return err
+	}
+	slog.InfoContext(ctx, "container placed", "slot", containerToPlace.Slot, "row", containerToPlace.Row, "tier", containerToPlace.Tier)
+	s.publish(ctx, ContainerEventPlaced, containerToPlace)
+	return nil
+}
+
+func (s *ContainerService) PickupContainer(ctx context.Context, yardName, containerNumber string) error {
+	container, err := s.ValidatePickup(ctx, yardName, containerNumber)
 	if err != nil {
 		return err
 	}
+	return s.CommitPickup(ctx, container)
+}
+
+// GetPlacedContainer mengambil kontainer yang ada di lapangan pada yard yang dimaksud
+func (s *ContainerService) GetPlacedContainer(ctx context.Context, yardName, containerNumber string) (*models.Container, error) {
+	container, err := s.Repo.GetContainerByNumber(ctx, containerNumber)
+	if err != nil {
+		return nil, err
+	}
 
 	if container.YardID != yardName {
-		return fmt.Errorf("container %s is not located in yard %s", containerNumber, yardName)
+		return nil, fmt.Errorf("container %s is not located in yard %s", containerNumber, yardName)
+	}
+	return container, nil
+}
+
+// ValidatePickup memastikan kontainer ada di lapangan pada yard yang dimaksud dan boleh keluar
+func (s *ContainerService) ValidatePickup(ctx context.Context, yardName, containerNumber string) (*models.Container, error) {
+	ctx = logging.With(ctx, logging.KeyContainer, containerNumber, logging.KeyYard, yardName)
+	container, err := s.GetPlacedContainer(ctx, yardName, containerNumber)
+	if err != nil {
+		slog.WarnContext(ctx, "pickup rejected", "error", err)
+		return nil, err
+	}
+
+	for _, validator := range s.PickupValidators {
+		if err := validator.CheckPickup(ctx, container); err != nil {
+			slog.WarnContext(containerLogContext(ctx, container), "pickup rejected", "error", err)
+			return nil, err
+		}
 	}
+	return container, nil
+}
 
+// CommitPickup menyimpan pickup kontainer hasil ValidatePickup
+func (s *ContainerService) CommitPickup(ctx context.Context, container *models.Container) error {
+	ctx = containerLogContext(ctx, container)
 	// Update status menjadi tidak ditempatkan
+	now := time.Now()
 	container.IsPlaced = false
-	return s.Repo.UpdateContainer(container)
+	container.PickedUpAt = &now
+	if err := s.Repo.UpdateContainer(ctx, container); err != nil {
+		slog.ErrorContext(ctx, "error saving pickup", "error", err)
+		return err
+	}
+	slog.InfoContext(ctx, "container picked up", "slot", container.Slot, "row", container.Row, "tier", container.Tier)
 	// Secara logika, posisi sekarang "kosong", GORM akan menyimpan perubahan IsPlaced
 	// Jika menggunakan cache Occupancy di Block, perlu diupdate juga disana.
 	// Kita abaikan cache Occupancy untuk sementara atau update saat load ulang.
+	s.publish(ctx, ContainerEventPickedUp, container)
+	return nil
+}
+
+// ValidateShift memvalidasi pemindahan kontainer ke posisi lain di yard yang sama dan mengembalikan kontainer
+// dengan posisi baru yang siap disimpan
+func (s *ContainerService) ValidateShift(ctx context.Context, yardName, containerNumber, blockName string, slot, row, tier int) (container *models.Container, err error) {
+	ctx = logging.With(ctx, logging.KeyContainer, containerNumber, logging.KeyYard, yardName, logging.KeyBlock, blockName)
+	defer func() {
+		if err != nil {
+			slog.WarnContext(ctx, "shift rejected", "slot", slot, "row", row, "tier", tier, "error", err)
+		}
+	}()
+
+	// PickupValidators (contoh: hold) sengaja tidak dijalankan. Hold menahan kontainer keluar dari yard, bukan
+	// berpindah di dalam yard; kontainer yang ditahan justru sering perlu dipindah ke area pemeriksaan.
+	container, err = s.GetPlacedContainer(ctx, yardName, containerNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// Kontainer yang tertimpa kontainer lain tidak bisa diangkat
+	above, err := s.Repo.GetContainersAbove(ctx, container)
+	if err != nil {
+		return nil, fmt.Errorf("error loading stacked containers: %v", err)
+	}
+	if len(above) > 0 {
+		return nil, fmt.Errorf("container %s has %d container(s) stacked above it", containerNumber, len(above))
+	}
+
+	block, err := s.Repo.GetBlockByName(ctx, blockName, yardName)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Repo.LoadBlockOccupancy(ctx, block); err != nil {
+		return nil, fmt.Errorf("error loading block occupancy: %v", err)
+	}
+	// Cell yang sedang ditempati kontainer ini kosong setelah kontainer diangkat, sehingga boleh dipakai lagi
+	// (contoh: 40ft bergeser satu slot)
+	if container.BlockID == blockName {
+		s.Repo.ReleasePosition(block, container.Slot, container.Row, container.Tier)
+		if container.Size == 40 {
+			s.Repo.ReleasePosition(block, container.Slot+1, container.Row, container.Tier)
+		}
+	}
+	if _, err := s.applyReservations(ctx, yardName, containerNumber, block); err != nil {
+		return nil, err
+	}
+
+	if slot < 1 || slot > block.TotalSlot || row < 1 || row > block.TotalRow || tier < 1 || tier > block.TotalTier {
+		return nil, fmt.Errorf("position out of bounds for block %s", blockName)
+	}
+	available := s.Repo.IsPositionAvailable(block, slot, row, tier)
+	if container.Size == 40 {
+		available = s.Repo.IsPositionAvailable40ft(block, slot, row, tier)
+	}
+	if !available {
+		return nil, fmt.Errorf("position %d-%d-%d in block %s is not available for %dft container", slot, row, tier, blockName, container.Size)
+	}
+
+	plans, err := s.Repo.GetPlansForSpec(ctx, yardName, blockName, container.Size, container.Height, container.Type)
+	if err != nil {
+		return nil, fmt.Errorf("error checking placement plan: %v", err)
+	}
+	if findCoveringPlan(plans, slot, row, tier, container.Size) == nil {
+		return nil, fmt.Errorf("shift location (%d-%d-%d) does not match planned area for container spec (size: %d, height: %.1f, type: %s) in block %s", slot, row, tier, container.Size, container.Height, container.Type, blockName)
+	}
+
+	container.BlockID = blockName
+	container.Slot, container.Row, container.Tier = slot, row, tier
+	return container, nil
+}
+
+// CommitShift menyimpan posisi baru kontainer hasil ValidateShift
+func (s *ContainerService) CommitShift(ctx context.Context, container *models.Container) error {
+	ctx = containerLogContext(ctx, container)
+	if err := s.Repo.UpdateContainer(ctx, container); err != nil {
+		slog.ErrorContext(ctx, "error saving shift", "error", err)
+		return err
+	}
+	slog.InfoContext(ctx, "container shifted", "slot", container.Slot, "row", container.Row, "tier", container.Tier)
+	s.publish(ctx, ContainerEventShifted, container)
+	return nil
+}
+
+// containerLogContext menambah nomor kontainer, yard dan block ke context log
+func containerLogContext(ctx context.Context, container *models.Container) context.Context {
+	return logging.With(ctx, logging.KeyContainer, container.ContainerNumber, logging.KeyYard, container.YardID, logging.KeyBlock, container.BlockID)
+}
+
+// findCoveringPlan mengembalikan plan pertama yang mencakup posisi (slot, row, tier), termasuk slot+1 untuk 40ft
+func findCoveringPlan(plans []models.YardPlan, slot, row, tier, size int) *models.YardPlan {
+	for i, p := range plans {
+		if tier >= p.MinTier && tier <= p.MaxTier &&
+			row >= p.MinRow && row <= p.MaxRow &&
+			slot >= p.MinSlot && slot <= p.MaxSlot {
+			if size == 40 && slot+1 > p.MaxSlot { // Jika 40ft, cek slot+1 juga
+				continue
+			}
+			return &plans[i]
+		}
+	}
+	return nil
+}
+
+func toPlanSummary(p *models.YardPlan) *schemas.PlanSummary {
+	if p == nil {
+		return nil
+	}
+	return &schemas.PlanSummary{
+		ID:            p.ID,
+		BlockID:       p.BlockID,
+		PlannedSize:   p.PlannedSize,
+		PlannedHeight: p.PlannedHeight,
+		PlannedType:   p.PlannedType,
+		MinSlot:       p.MinSlot,
+		MaxSlot:       p.MaxSlot,
+		MinRow:        p.MinRow,
+		MaxRow:        p.MaxRow,
+		MinTier:       p.MinTier,
+		MaxTier:       p.MaxTier,
+	}
+}
+
+func (s *ContainerService) GetContainerDetail(ctx context.Context, containerNumber string) (*schemas.ContainerDetailResponse, error) {
+	container, err := s.Repo.FindContainerByNumber(ctx, containerNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	response := schemas.ContainerDetailResponse{
+		ContainerNumber: container.ContainerNumber,
+		ContainerSize:   container.Size,
+		ContainerHeight: container.Height,
+		ContainerType:   container.Type,
+		IsPlaced:        container.IsPlaced,
+		StackedAbove:    []models.Container{},
+	}
+
+	// Posisi, plan dan tumpukan hanya relevan jika kontainer masih di lapangan
+	if !container.IsPlaced {
+		return &response, nil
+	}
+
+	response.Position = &schemas.ContainerPosition{
+		Yard:  container.YardID,
+		Block: container.BlockID,
+		Slot:  container.Slot,
+		Row:   container.Row,
+		Tier:  container.Tier,
+	}
+
+	plans, err := s.Repo.GetPlansForSpec(ctx, container.YardID, container.BlockID, container.Size, container.Height, container.Type)
+	if err != nil {
+		return nil, fmt.Errorf("error checking placement plan: %v", err)
+	}
+	response.Plan = toPlanSummary(findCoveringPlan(plans, container.Slot, container.Row, container.Tier, container.Size))
+
+	above, err := s.Repo.GetContainersAbove(ctx, container)
+	if err != nil {
+		return nil, fmt.Errorf("error loading stacked containers: %v", err)
+	}
+	response.StackedAbove = above
+
+	return &response, nil
+}
+
+func (s *ContainerService) SearchContainers(ctx context.Context, req schemas.SearchContainerRequest) (*schemas.SearchContainerResponse, error) {
+	if req.Page < 1 {
+		req.Page = 1
+	}
+	if req.Limit < 1 {
+		req.Limit = 20
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	filter := repositories.ContainerFilter{
+		YardID:  req.Yard,
+		BlockID: req.Block,
+		Type:    req.Type,
+		Size:    req.Size,
+		Offset:  (req.Page - 1) * req.Limit,
+		Limit:   req.Limit,
+	}
+	switch req.Status {
+	case "":
+	case "placed":
+		placed := true
+		filter.IsPlaced = &placed
+	case "picked_up":
+		placed := false
+		filter.IsPlaced = &placed
+	default:
+		return nil, fmt.Errorf("unsupported status filter: %s", req.Status)
+	}
+
+	containers, total, err := s.Repo.SearchContainers(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schemas.SearchContainerResponse{
+		Containers: containers,
+		Pagination: schemas.Pagination{
+			Page:  req.Page,
+			Limit: req.Limit,
+			Total: total,
+		},
+	}, nil
 }
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"yard-calculation/config"
+	"yard-calculation/metrics"
+	"yard-calculation/repositories"
+
+	"gorm.io/gorm"
+)
+
+// ContainerServices berisi ContainerService beserta service yang memesan posisi, memvalidasi dan mendengarkan
+// perubahan kontainer. Server dan CLI membangunnya lewat NewContainerServices supaya penempatan dan pickup dari
+// mana pun mengikuti aturan yang sama.
+type ContainerServices struct {
+	Container *ContainerService
+	WorkQueue *WorkQueueService
+	PreAdvice *PreAdviceService
+	Hold      *HoldService
+	Codeco    *CodecoService
+}
+
+// NewContainerServices memasang reserver, validator dan listener ContainerService sesuai konfigurasi.
+// occupancy boleh nil jika gauge occupancy tidak dipakai (contoh: CLI).
+func NewContainerServices(db *gorm.DB, cfg *config.Config, occupancy *metrics.OccupancyCollector) *ContainerServices {
+	containerRepo := repositories.NewContainerRepository(db)
+	preAdviceRepo := repositories.NewPreAdviceRepository(db)
+	containerService := NewContainerService(containerRepo)
+
+	// CODECO keluar untuk shipping line setiap ada penempatan dan pickup
+	containerService.AddListener(NewCodecoOutboxService(repositories.NewEDIOutboxRepository(db), containerRepo, cfg.EDI))
+
+	// Posisi tujuan job yang belum dikonfirmasi dianggap terisi saat mencari dan memvalidasi posisi
+	workQueueService := NewWorkQueueService(repositories.NewWorkJobRepository(db), repositories.NewEquipmentRepository(db), containerService)
+	containerService.AddReserver(workQueueService)
+
+	// Booking truk memesan posisi yard, penempatan saat gate-in harus sesuai posisi yang dipesan
+	preAdviceService := NewPreAdviceService(preAdviceRepo, containerService, cfg.PreAdvice.Grace)
+	if cfg.Features.PreAdviceReservation {
+		containerService.AddReserver(preAdviceService)
+		containerService.AddValidator(preAdviceService)
+	}
+	containerService.AddDefaulter(preAdviceService)
+	containerService.AddListener(preAdviceService)
+
+	// Penempatan dan pickup per yard/block untuk /metrics
+	containerService.AddListener(MetricsListener{Occupancy: occupancy})
+
+	// Pickup ditolak selama kontainer masih punya hold aktif
+	holdService := NewHoldService(repositories.NewHoldRepository(db), containerRepo)
+	if cfg.Features.HoldCheck {
+		containerService.AddPickupValidator(holdService)
+	}
+
+	return &ContainerServices{
+		Container: containerService,
+		WorkQueue: workQueueService,
+		PreAdvice: preAdviceService,
+		Hold:      holdService,
+		Codeco:    NewCodecoService(containerService, workQueueService, preAdviceRepo),
+	}
+}
@@ -0,0 +1,315 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+	"yard-calculation/models"
+	"yard-calculation/repositories"
+	"yard-calculation/schemas"
+)
+
+// eventRecorder mencatat event kontainer yang dipublikasikan ContainerService
+type eventRecorder struct {
+	events []string
+}
+
+func (r *eventRecorder) HandleContainerEvent(_ context.Context, eventType string, container *models.Container) {
+	r.events = append(r.events, eventType+" "+container.ContainerNumber)
+}
+
+// rejectPickup menolak pickup semua kontainer, seperti hold aktif
+type rejectPickup struct{}
+
+func (rejectPickup) CheckPickup(_ context.Context, container *models.Container) error {
+	return errors.New("container " + container.ContainerNumber + " has active hold")
+}
+
+// staticReserver memesan posisi tetap, seperti pre-advice atau job yang belum selesai
+type staticReserver []models.Container
+
+func (r staticReserver) ReservedPositions(context.Context, string) ([]models.Container, error) {
+	return slices.Clone(r), nil
+}
+
+// newTestContainerService membuat ContainerService di atas MemoryRepository dengan yard YRD1: block A (5 slot,
+// 2 row, 3 tier) dengan rencana 20ft DRY di slot 1-2 dan 40ft DRY di slot 3-5, dan block B di yard YRD2
+func newTestContainerService(t *testing.T) (*ContainerService, *eventRecorder) {
+	t.Helper()
+	repo := repositories.NewMemoryRepository()
+	err := repo.ImportLayout(context.Background(),
+		[]models.Yard{{ID: "YRD1", Name: "Yard 1"}, {ID: "YRD2", Name: "Yard 2"}},
+		[]models.Block{
+			{ID: "A", Name: "Block A", YardID: "YRD1", TotalSlot: 5, TotalRow: 2, TotalTier: 3},
+			{ID: "B", Name: "Block B", YardID: "YRD2", TotalSlot: 5, TotalRow: 2, TotalTier: 3},
+		},
+		[]models.YardPlan{
+			{YardID: "YRD1", BlockID: "A", PlannedSize: 20, PlannedHeight: 8.6, PlannedType: "DRY", MinSlot: 1, MaxSlot: 2, MinRow: 1, MaxRow: 2, MinTier: 1, MaxTier: 3},
+			{YardID: "YRD1", BlockID: "A", PlannedSize: 40, PlannedHeight: 9.6, PlannedType: "DRY", MinSlot: 3, MaxSlot: 5, MinRow: 1, MaxRow: 2, MinTier: 1, MaxTier: 3},
+			{YardID: "YRD2", BlockID: "B", PlannedSize: 20, PlannedHeight: 8.6, PlannedType: "DRY", MinSlot: 1, MaxSlot: 5, MinRow: 1, MaxRow: 2, MinTier: 1, MaxTier: 3},
+		})
+	if err != nil {
+		t.Fatalf("import layout: %v", err)
+	}
+	recorder := &eventRecorder{}
+	service := NewContainerService(repo)
+	service.AddListener(recorder)
+	return service, recorder
+}
+
+func TestContainerServicePlacement(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name                  string
+		number, block         string
+		slot, row, tier, size int
+		height                float64
+		wantErr               string
+	}{
+		{"20ft in plan", "MSKU0000001", "A", 1, 1, 1, 20, 8.6, ""},
+		{"40ft in plan", "MSKU0000002", "A", 3, 1, 1, 40, 9.6, ""},
+		{"already placed", "MSKU0000001", "A", 2, 1, 1, 20, 8.6, "container with number MSKU0000001 is already placed at A-1-1-1"},
+		{"occupied", "MSKU0000003", "A", 1, 1, 1, 20, 8.6, "position 1-1-1 in block A is occupied"},
+		{"40ft overlaps 40ft", "MSKU0000004", "A", 4, 1, 1, 40, 9.6, "positions 4-1-1 and 5-1-1 in block A are not available for 40ft container"},
+		{"40ft past last slot", "MSKU0000004", "A", 5, 2, 1, 40, 9.6, "positions 5-2-1 and 6-2-1 in block A are not available for 40ft container"},
+		{"out of bounds", "MSKU0000005", "A", 1, 3, 1, 20, 8.6, "position out of bounds for block A"},
+		{"outside plan", "MSKU0000005", "A", 4, 2, 1, 20, 8.6, "placement location (4-2-1) does not match planned area for container spec (size: 20, height: 8.6, type: DRY) in block A"},
+		{"no plan for spec", "MSKU0000005", "A", 1, 2, 1, 20, 9.6, "no plan found for container spec (size: 20, height: 9.6, type: DRY) at placement location in block A"},
+		{"block in other yard", "MSKU0000005", "B", 1, 1, 1, 20, 8.6, "block with name B in yard YRD1 not found"},
+	}
+
+	service, recorder := newTestContainerService(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.PlaceContainerDetailed(ctx, "YRD1", tt.number, tt.block, tt.slot, tt.row, tt.tier, tt.size, tt.height, "DRY", "MSK", models.CargoStatusFull)
+			if gotErr := errText(err); gotErr != tt.wantErr {
+				t.Fatalf("PlaceContainerDetailed() error = %q, want %q", gotErr, tt.wantErr)
+			}
+		})
+	}
+
+	want := []string{"PLACED MSKU0000001", "PLACED MSKU0000002"}
+	if !slices.Equal(recorder.events, want) {
+		t.Errorf("events = %v, want %v", recorder.events, want)
+	}
+	placed, err := service.GetPlacedContainer(ctx, "YRD1", "MSKU0000002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !placed.IsPlaced || placed.PlacedAt == nil || placed.Slot != 3 || placed.CargoStatus != models.CargoStatusFull {
+		t.Errorf("placed container = %+v", placed)
+	}
+}
+
+func TestContainerServiceSuggestion(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestContainerService(t)
+
+	want := []schemas.SuggestContainerResponse{
+		{Yard: "YRD1", Block: "A", Slot: 1, Row: 1, Tier: 1},
+		{Yard: "YRD1", Block: "A", Slot: 2, Row: 1, Tier: 1},
+		{Yard: "YRD1", Block: "A", Slot: 1, Row: 2, Tier: 1},
+	}
+	for i, w := range want {
+		number := []string{"MSKU0000001", "MSKU0000002", "MSKU0000003"}[i]
+		got, err := service.GetSuggestedPosition(ctx, "YRD1", number, 20, 8.6, "DRY")
+		if err != nil {
+			t.Fatalf("suggestion %d: %v", i, err)
+		}
+		if *got != w {
+			t.Fatalf("suggestion %d = %+v, want %+v", i, *got, w)
+		}
+		// Saran berikutnya harus melewati posisi yang sudah ditempati
+		if err := service.PlaceContainerDetailed(ctx, "YRD1", number, got.Block, got.Slot, got.Row, got.Tier, 20, 8.6, "DRY", "", models.CargoStatusFull); err != nil {
+			t.Fatalf("place suggestion %d: %v", i, err)
+		}
+	}
+
+	if _, err := service.GetSuggestedPosition(ctx, "YRD9", "MSKU0000004", 20, 8.6, "DRY"); errText(err) != "yard with name YRD9 not found" {
+		t.Errorf("unknown yard error = %v", err)
+	}
+	if _, err := service.GetSuggestedPosition(ctx, "YRD1", "MSKU0000004", 20, 8.6, "REEFER"); err == nil {
+		t.Error("expected error for spec without plan")
+	}
+}
+
+func TestContainerServiceBatchReservations(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestContainerService(t)
+	service.AddReserver(staticReserver{
+		{ContainerNumber: "MSKU0000009", YardID: "YRD1", BlockID: "A", Slot: 1, Row: 1, Tier: 1, Size: 20, Height: 8.6, Type: "DRY"},
+		{ContainerNumber: "MSKU0000002", YardID: "YRD1", BlockID: "A", Slot: 2, Row: 1, Tier: 1, Size: 20, Height: 8.6, Type: "DRY"},
+		{ContainerNumber: "MSKU0000003", YardID: "YRD1", BlockID: "A", Slot: 3, Row: 1, Tier: 1, Size: 40, Height: 9.6, Type: "DRY"},
+	})
+
+	items := []schemas.BatchSuggestItem{
+		{ContainerNumber: "MSKU0000001", ContainerSize: 20, ContainerHeight: 8.6, ContainerType: "DRY"},
+		{ContainerNumber: "MSKU0000002", ContainerSize: 20, ContainerHeight: 8.6, ContainerType: "DRY"},
+		{ContainerNumber: "MSKU0000003", ContainerSize: 20, ContainerHeight: 8.6, ContainerType: "DRY"},
+	}
+	response, err := service.SuggestBatch(ctx, "YRD1", items)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Posisi pesanan kontainer lain dilewati, kontainer yang memesan mendapat posisinya sendiri
+	want := []string{"A-1-2-1", "A-2-1-1", ""}
+	for i, result := range response.Results {
+		got := ""
+		if p := result.SuggestedPosition; p != nil {
+			got = fmt.Sprintf("%s-%d-%d-%d", p.Block, p.Slot, p.Row, p.Tier)
+		}
+		if got != want[i] {
+			t.Errorf("%s suggested at %q, want %q (reason %s: %s)", result.ContainerNumber, got, want[i], result.Reason, result.Error)
+		}
+	}
+	if reason := response.Results[2].Reason; reason != schemas.BatchReasonReservationMismatch {
+		t.Errorf("mismatched reservation reason = %q", reason)
+	}
+	if response.Allocated != 2 || response.Unallocated != 1 {
+		t.Errorf("allocated %d, unallocated %d; want 2 and 1", response.Allocated, response.Unallocated)
+	}
+}
+
+func TestContainerServicePickup(t *testing.T) {
+	ctx := context.Background()
+	service, recorder := newTestContainerService(t)
+	for _, p := range []struct {
+		number    string
+		slot, row int
+		tier      int
+	}{{"MSKU0000001", 1, 1, 1}, {"MSKU0000002", 2, 1, 1}, {"MSKU0000003", 2, 2, 1}} {
+		if err := service.PlaceContainerDetailed(ctx, "YRD1", p.number, "A", p.slot, p.row, p.tier, 20, 8.6, "DRY", "MSK", models.CargoStatusFull); err != nil {
+			t.Fatalf("place %s: %v", p.number, err)
+		}
+	}
+	recorder.events = nil
+
+	if err := service.PickupContainer(ctx, "YRD2", "MSKU0000001"); errText(err) != "container MSKU0000001 is not located in yard YRD2" {
+		t.Errorf("pickup from other yard error = %v", err)
+	}
+	if err := service.PickupContainer(ctx, "YRD1", "MSKU0000001"); err != nil {
+		t.Fatalf("pickup: %v", err)
+	}
+	if err := service.PickupContainer(ctx, "YRD1", "MSKU0000001"); errText(err) != "container with number MSKU0000001 not found or not placed" {
+		t.Errorf("second pickup error = %v", err)
+	}
+
+	picked, err := service.Repo.FindContainerByNumber(ctx, "MSKU0000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if picked.IsPlaced || picked.PickedUpAt == nil {
+		t.Errorf("picked up container = %+v", picked)
+	}
+
+	// Posisi yang dilepas bisa dipakai kontainer lain
+	if err := service.PlaceContainerDetailed(ctx, "YRD1", "MSKU0000004", "A", 1, 1, 1, 20, 8.6, "DRY", "MSK", models.CargoStatusFull); err != nil {
+		t.Errorf("place at released position: %v", err)
+	}
+
+	service.AddPickupValidator(rejectPickup{})
+	if err := service.PickupContainer(ctx, "YRD1", "MSKU0000003"); errText(err) != "container MSKU0000003 has active hold" {
+		t.Errorf("rejected pickup error = %v", err)
+	}
+	if _, err := service.GetPlacedContainer(ctx, "YRD1", "MSKU0000003"); err != nil {
+		t.Errorf("rejected pickup must keep the container placed: %v", err)
+	}
+
+	want := []string{"PICKED_UP MSKU0000001", "PLACED MSKU0000004"}
+	if !slices.Equal(recorder.events, want) {
+		t.Errorf("events = %v, want %v", recorder.events, want)
+	}
+}
+
+func TestContainerServiceShift(t *testing.T) {
+	ctx := context.Background()
+	service, recorder := newTestContainerService(t)
+	if err := service.PlaceContainerDetailed(ctx, "YRD1", "MSKU0000001", "A", 3, 1, 1, 40, 9.6, "DRY", "MSK", models.CargoStatusFull); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.PlaceContainerDetailed(ctx, "YRD1", "MSKU0000002", "A", 1, 1, 1, 20, 8.6, "DRY", "MSK", models.CargoStatusFull); err != nil {
+		t.Fatal(err)
+	}
+
+	// 40ft bergeser satu slot ke cell yang sebagian masih ditempatinya sendiri
+	container, err := service.ValidateShift(ctx, "YRD1", "MSKU0000001", "A", 4, 1, 1)
+	if err != nil {
+		t.Fatalf("shift onto own cells: %v", err)
+	}
+	if err := service.CommitShift(ctx, container); err != nil {
+		t.Fatal(err)
+	}
+	shifted, err := service.GetPlacedContainer(ctx, "YRD1", "MSKU0000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shifted.Slot != 4 {
+		t.Errorf("shifted slot = %d, want 4", shifted.Slot)
+	}
+
+	if _, err := service.ValidateShift(ctx, "YRD1", "MSKU0000002", "A", 4, 1, 1); errText(err) != "position 4-1-1 in block A is not available for 20ft container" {
+		t.Errorf("shift onto other container error = %v", err)
+	}
+
+	want := []string{"PLACED MSKU0000001", "PLACED MSKU0000002", "SHIFTED MSKU0000001"}
+	if !slices.Equal(recorder.events, want) {
+		t.Errorf("events = %v, want %v", recorder.events, want)
+	}
+}
+
+func TestContainerServiceRevisit(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestContainerService(t)
+	from := time.Now().Add(-time.Hour)
+
+	place := func(slot int) {
+		t.Helper()
+		if err := service.PlaceContainerDetailed(ctx, "YRD1", "MSKU0000001", "A", slot, 1, 1, 20, 8.6, "DRY", "MSK", models.CargoStatusFull); err != nil {
+			t.Fatalf("place at slot %d: %v", slot, err)
+		}
+	}
+	place(1)
+	if err := service.PickupContainer(ctx, "YRD1", "MSKU0000001"); err != nil {
+		t.Fatalf("pickup: %v", err)
+	}
+	// Kontainer yang kembali dicatat sebagai kunjungan baru, kunjungan pertama tetap tersimpan
+	place(2)
+
+	visits, err := service.Repo.GetVisitsInRange(ctx, "YRD1", "", from, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(visits) != 2 {
+		t.Fatalf("got %d visits, want 2: %+v", len(visits), visits)
+	}
+	var first, second models.Container
+	for _, v := range visits {
+		if v.IsPlaced {
+			second = v
+		} else {
+			first = v
+		}
+	}
+	if first.Slot != 1 || first.PickedUpAt == nil || second.Slot != 2 || second.PickedUpAt != nil || first.ID == second.ID {
+		t.Errorf("visits = %+v", visits)
+	}
+
+	latest, err := service.Repo.FindContainerByNumber(ctx, "MSKU0000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.ID != second.ID {
+		t.Errorf("FindContainerByNumber returned visit %d, want latest visit %d", latest.ID, second.ID)
+	}
+}
+
+func errText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
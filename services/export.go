@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+	"yard-calculation/models"
+	"yard-calculation/repositories"
+	"yard-calculation/schemas"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var inventoryHeader = []string{"container_number", "container_size", "container_height", "container_type", "yard", "block", "slot", "row", "tier", "placed_at"}
+
+type ExportService struct {
+	Repo repositories.Store
+}
+
+func NewExportService(repo repositories.Store) *ExportService {
+	return &ExportService{Repo: repo}
+}
+
+// InventoryFilter mengubah request export menjadi filter repository, hanya kontainer yang masih di lapangan
+func InventoryFilter(req schemas.ExportInventoryRequest) (repositories.ContainerFilter, error) {
+	placed := true
+	filter := repositories.ContainerFilter{
+		YardID:   req.Yard,
+		BlockID:  req.Block,
+		Type:     req.Type,
+		IsPlaced: &placed,
+	}
+	if req.From != "" {
+		from, err := time.ParseInLocation(time.DateOnly, req.From, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf("invalid from date %s, expected format YYYY-MM-DD", req.From)
+		}
+		filter.PlacedFrom = &from
+	}
+	if req.To != "" {
+		to, err := time.ParseInLocation(time.DateOnly, req.To, time.Local)
+		if err != nil {
+			return filter, fmt.Errorf("invalid to date %s, expected format YYYY-MM-DD", req.To)
+		}
+		// Tanggal akhir inklusif, jadi batas atas adalah awal hari berikutnya
+		until := to.AddDate(0, 0, 1)
+		filter.PlacedUntil = &until
+	}
+	return filter, nil
+}
+
+// ExportInventory menulis inventory ke w baris per baris dalam format csv, jsonl atau xlsx
+func (s *ExportService) ExportInventory(ctx context.Context, w io.Writer, format string, filter repositories.ContainerFilter) error {
+	switch format {
+	case "csv":
+		return s.exportCSV(ctx, w, filter)
+	case "jsonl":
+		return s.exportJSONLines(ctx, w, filter)
+	case "xlsx":
+		return s.exportXLSX(ctx, w, filter)
+	default:
+		return fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+func (s *ExportService) exportCSV(ctx context.Context, w io.Writer, filter repositories.ContainerFilter) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(inventoryHeader); err != nil {
+		return err
+	}
+	err := s.Repo.StreamContainers(ctx, filter, func(c *models.Container) error {
+		row := toInventoryRow(c)
+		return writer.Write([]string{
+			row.ContainerNumber,
+			strconv.Itoa(row.ContainerSize),
+			strconv.FormatFloat(row.ContainerHeight, 'f', -1, 64),
+			row.ContainerType,
+			row.Yard,
+			row.Block,
+			strconv.Itoa(row.Slot),
+			strconv.Itoa(row.Row),
+			strconv.Itoa(row.Tier),
+			row.PlacedAt,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func (s *ExportService) exportJSONLines(ctx context.Context, w io.Writer, filter repositories.ContainerFilter) error {
+	encoder := json.NewEncoder(w)
+	return s.Repo.StreamContainers(ctx, filter, func(c *models.Container) error {
+		return encoder.Encode(toInventoryRow(c))
+	})
+}
+
+func (s *ExportService) exportXLSX(ctx context.Context, w io.Writer, filter repositories.ContainerFilter) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	const sheet = "Sheet1"
+	// StreamWriter menyimpan baris ke file sementara, bukan ke memori
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+
+	header := make([]interface{}, len(inventoryHeader))
+	for i, h := range inventoryHeader {
+		header[i] = h
+	}
+	if err := sw.SetRow("A1", header); err != nil {
+		return err
+	}
+
+	rowNum := 2
+	err = s.Repo.StreamContainers(ctx, filter, func(c *models.Container) error {
+		row := toInventoryRow(c)
+		cell, err := excelize.CoordinatesToCellName(1, rowNum)
+		if err != nil {
+			return err
+		}
+		rowNum++
+		return sw.SetRow(cell, []interface{}{
+			row.ContainerNumber, row.ContainerSize, row.ContainerHeight, row.ContainerType,
+			row.Yard, row.Block, row.Slot, row.Row, row.Tier, row.PlacedAt,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if err := sw.Flush(); err != nil {
+		return err
+	}
+	return f.Write(w)
+}
+
+func toInventoryRow(c *models.Container) schemas.InventoryRow {
+	row := schemas.InventoryRow{
+		ContainerNumber: c.ContainerNumber,
+		ContainerSize:   c.Size,
+		ContainerHeight: c.Height,
+		ContainerType:   c.Type,
+		Yard:            c.YardID,
+		Block:           c.BlockID,
+		Slot:            c.Slot,
+		Row:             c.Row,
+		Tier:            c.Tier,
+	}
+	// Baris lama tanpa placed_at memakai created_at, sama dengan filter tanggal
+	placedAt := c.CreatedAt
+	if c.PlacedAt != nil {
+		placedAt = *c.PlacedAt
+	}
+	if !placedAt.IsZero() {
+		row.PlacedAt = placedAt.Format(time.RFC3339)
+	}
+	return row
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"yard-calculation/auth"
+	"yard-calculation/models"
+	"yard-calculation/repositories"
+	"yard-calculation/schemas"
+)
+
+// HoldService mengelola hold kontainer dan menolak pickup selama masih ada hold aktif (PickupValidator).
+// Hold boleh dibuat sebelum kontainer tiba, contoh: hold bea cukai dari manifest.
+type HoldService struct {
+	Repo       *repositories.HoldRepository
+	Containers repositories.ContainerStore
+}
+
+func NewHoldService(repo *repositories.HoldRepository, containers repositories.ContainerStore) *HoldService {
+	return &HoldService{Repo: repo, Containers: containers}
+}
+
+// checkContainerYard memastikan identitas di ctx boleh mengakses yard tempat kontainer berada. Kontainer yang belum
+// tiba atau sudah keluar tidak punya yard, sehingga hold-nya boleh dikelola identitas dengan permission hold mana pun.
+func (s *HoldService) checkContainerYard(ctx context.Context, containerNumber string) error {
+	container, err := s.Containers.GetContainerByNumber(ctx, containerNumber)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("container with number %s not found or not placed", containerNumber) {
+			return nil
+		}
+		return err
+	}
+	return auth.CheckYard(ctx, container.YardID)
+}
+
+func (s *HoldService) AddHold(ctx context.Context, req schemas.CreateHoldRequest) (*models.Hold, error) {
+	if err := s.checkContainerYard(ctx, req.ContainerNumber); err != nil {
+		return nil, err
+	}
+	active, err := s.Repo.GetActiveHolds(ctx, req.ContainerNumber)
+	if err != nil {
+		return nil, err
+	}
+	for _, hold := range active {
+		if hold.HoldType == req.HoldType {
+			return nil, fmt.Errorf("container %s already has active %s hold %d", req.ContainerNumber, req.HoldType, hold.ID)
+		}
+	}
+
+	hold := &models.Hold{
+		ContainerNumber: req.ContainerNumber,
+		HoldType:        req.HoldType,
+		Reason:          req.Reason,
+		CreatedBy:       req.CreatedBy,
+	}
+	if err := s.Repo.CreateHold(ctx, hold); err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+func (s *HoldService) ReleaseHold(ctx context.Context, id uint, req schemas.ReleaseHoldRequest) (*models.Hold, error) {
+	hold, err := s.Repo.GetHold(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkContainerYard(ctx, hold.ContainerNumber); err != nil {
+		return nil, err
+	}
+	if hold.ReleasedAt != nil {
+		return nil, fmt.Errorf("hold %d is already released", id)
+	}
+
+	now := time.Now()
+	hold.ReleasedAt = &now
+	hold.ReleasedBy = req.ReleasedBy
+	hold.ReleaseReason = req.Reason
+	if err := s.Repo.UpdateHold(ctx, hold); err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+func (s *HoldService) ListHolds(ctx context.Context, req schemas.ListHoldRequest) ([]models.Hold, error) {
+	filter := repositories.HoldFilter{ContainerNumber: req.ContainerNumber, HoldType: req.HoldType}
+	if req.Status != "" {
+		active := req.Status == "active"
+		filter.Active = &active
+	}
+	return s.Repo.ListHolds(ctx, filter)
+}
+
+// CheckPickup memenuhi PickupValidator
+func (s *HoldService) CheckPickup(ctx context.Context, container *models.Container) error {
+	active, err := s.Repo.GetActiveHolds(ctx, container.ContainerNumber)
+	if err != nil {
+		return fmt.Errorf("error checking holds: %v", err)
+	}
+	if len(active) == 0 {
+		return nil
+	}
+
+	reasons := make([]string, len(active))
+	for i, hold := range active {
+		reasons[i] = fmt.Sprintf("%s (%s)", hold.HoldType, hold.Reason)
+	}
+	return fmt.Errorf("container %s has active hold: %s", container.ContainerNumber, strings.Join(reasons, ", "))
+}
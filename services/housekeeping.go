@@ -0,0 +1,353 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"yard-calculation/models"
+	"yard-calculation/repositories"
+	"yard-calculation/schemas"
+)
+
+// Alasan pemindahan housekeeping
+const (
+	HousekeepingReasonOutOfPlan  = "OUT_OF_PLAN"
+	HousekeepingReasonMixedStack = "MIXED_STACK"
+)
+
+// Bobot satu kontainer di luar rencana dibanding satu rehandle, supaya kontainer di luar rencana diprioritaskan
+const misplacedWeight = 10
+
+// HousekeepingService memakai reserver ContainerService supaya posisi tujuan job dan pre-advice tidak dipakai
+type HousekeepingService struct {
+	Repo             repositories.Store
+	ContainerService *ContainerService
+}
+
+func NewHousekeepingService(containerService *ContainerService) *HousekeepingService {
+	return &HousekeepingService{Repo: containerService.Repo, ContainerService: containerService}
+}
+
+// PlanHousekeeping menghitung urutan pemindahan (shift) di dalam satu block untuk memindahkan kontainer ke
+// rencana yang sesuai dan mengurangi tumpukan campuran. Setiap langkah memilih pemindahan kontainer paling
+// atas dengan perbaikan terbesar, sampai batas MaxMoves atau tidak ada lagi pemindahan yang memperbaiki.
+// Tidak ada data yang disimpan, hasilnya daftar pekerjaan.
+func (s *HousekeepingService) PlanHousekeeping(ctx context.Context, yardName, blockName string, req schemas.HousekeepingRequest) (*schemas.HousekeepingResponse, error) {
+	if req.MaxMoves <= 0 {
+		req.MaxMoves = 20
+	}
+	if req.GroupBy == "" {
+		req.GroupBy = "spec"
+	}
+	if req.GroupBy != "spec" && req.GroupBy != "line_operator" {
+		return nil, fmt.Errorf("unsupported group_by: %s", req.GroupBy)
+	}
+
+	block, err := s.Repo.GetBlockByName(ctx, blockName, yardName)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Repo.LoadBlockOccupancy(ctx, block); err != nil {
+		return nil, fmt.Errorf("error loading block occupancy: %v", err)
+	}
+
+	plans, err := s.targetPlans(ctx, yardName, block, req.TargetPlans)
+	if err != nil {
+		return nil, err
+	}
+
+	g := newStackGrid(block, plans, req.GroupBy)
+	// Posisi yang dipesan job atau pre-advice tidak boleh menjadi tujuan, dan kontainer di bawahnya tidak dipindah
+	reserved, err := s.ContainerService.reservedPositions(ctx, block.YardID)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range reserved {
+		if c.BlockID == block.ID {
+			g.reserve(c)
+		}
+	}
+	response := schemas.HousekeepingResponse{
+		Yard:            yardName,
+		Block:           block.ID,
+		MaxMoves:        req.MaxMoves,
+		MisplacedBefore: g.misplacedCount(),
+		RehandlesBefore: g.rehandleCount(),
+		Moves:           []schemas.HousekeepingMove{},
+	}
+
+	for len(response.Moves) < req.MaxMoves {
+		move, ok := g.bestMove()
+		if !ok {
+			break
+		}
+		from := schemas.ContainerPosition{Yard: yardName, Block: block.ID, Slot: move.c.Slot, Row: move.c.Row, Tier: move.c.Tier}
+		g.move(move.c, move.slot, move.row, move.tier)
+		response.Moves = append(response.Moves, schemas.HousekeepingMove{
+			Sequence:        len(response.Moves) + 1,
+			ContainerNumber: move.c.ContainerNumber,
+			From:            from,
+			To:              schemas.ContainerPosition{Yard: yardName, Block: block.ID, Slot: move.slot, Row: move.row, Tier: move.tier},
+			Reason:          move.reason,
+		})
+	}
+
+	response.MisplacedAfter = g.misplacedCount()
+	response.RehandlesAfter = g.rehandleCount()
+	return &response, nil
+}
+
+func (s *HousekeepingService) targetPlans(ctx context.Context, yardName string, block *models.Block, target []schemas.PlanSummary) ([]models.YardPlan, error) {
+	if len(target) == 0 {
+		return s.Repo.GetPlansForBlock(ctx, yardName, block.ID)
+	}
+	plans := make([]models.YardPlan, 0, len(target))
+	for _, p := range target {
+		if p.MinSlot < 1 || p.MinRow < 1 || p.MinTier < 1 || p.MinSlot > p.MaxSlot || p.MinRow > p.MaxRow || p.MinTier > p.MaxTier {
+			return nil, fmt.Errorf("invalid target plan range for spec (size: %d, height: %.1f, type: %s)", p.PlannedSize, p.PlannedHeight, p.PlannedType)
+		}
+		plans = append(plans, models.YardPlan{
+			ID:            p.ID,
+			YardID:        yardName,
+			BlockID:       block.ID,
+			PlannedSize:   p.PlannedSize,
+			PlannedHeight: p.PlannedHeight,
+			PlannedType:   p.PlannedType,
+			MinSlot:       p.MinSlot,
+			MaxSlot:       p.MaxSlot,
+			MinRow:        p.MinRow,
+			MaxRow:        p.MaxRow,
+			MinTier:       p.MinTier,
+			MaxTier:       p.MaxTier,
+		})
+	}
+	return plans, nil
+}
+
+// stackGrid adalah salinan occupancy block di memori yang bisa diubah selama perencanaan
+type stackGrid struct {
+	block   *models.Block
+	plans   []models.YardPlan
+	groupBy string
+	cells   map[[3]int]*models.Container // Key: {slot, row, tier}, kontainer 40ft menempati dua cell
+	// Cell yang dipesan tapi belum ditempati, tidak bisa menjadi tujuan maupun penopang
+	reserved map[[3]int]bool
+}
+
+type housekeepingMove struct {
+	c               *models.Container
+	slot, row, tier int
+	reason          string
+	score           int
+}
+
+func newStackGrid(block *models.Block, plans []models.YardPlan, groupBy string) *stackGrid {
+	g := &stackGrid{block: block, plans: plans, groupBy: groupBy, cells: make(map[[3]int]*models.Container), reserved: make(map[[3]int]bool)}
+	seen := make(map[uint]*models.Container)
+	for _, c := range block.OccupiedBy {
+		if _, ok := seen[c.ID]; ok {
+			continue
+		}
+		copied := c
+		seen[c.ID] = &copied
+		g.place(&copied)
+	}
+	return g
+}
+
+func (g *stackGrid) slots(c *models.Container) []int {
+	if c.Size == 40 {
+		return []int{c.Slot, c.Slot + 1}
+	}
+	return []int{c.Slot}
+}
+
+func (g *stackGrid) place(c *models.Container) {
+	for _, slot := range g.slots(c) {
+		g.cells[[3]int{slot, c.Row, c.Tier}] = c
+	}
+}
+
+func (g *stackGrid) reserve(c models.Container) {
+	for _, slot := range g.slots(&c) {
+		g.reserved[[3]int{slot, c.Row, c.Tier}] = true
+	}
+}
+
+func (g *stackGrid) remove(c *models.Container) {
+	for _, slot := range g.slots(c) {
+		delete(g.cells, [3]int{slot, c.Row, c.Tier})
+	}
+}
+
+func (g *stackGrid) move(c *models.Container, slot, row, tier int) {
+	g.remove(c)
+	c.Slot, c.Row, c.Tier = slot, row, tier
+	g.place(c)
+}
+
+func (g *stackGrid) group(c *models.Container) string {
+	if g.groupBy == "line_operator" {
+		return c.LineOperator
+	}
+	return fmt.Sprintf("%d/%.1f/%s", c.Size, c.Height, c.Type)
+}
+
+// isTop bernilai true jika tidak ada kontainer atau cell yang dipesan di atas c
+func (g *stackGrid) isTop(c *models.Container) bool {
+	for _, slot := range g.slots(c) {
+		above := [3]int{slot, c.Row, c.Tier + 1}
+		if _, ok := g.cells[above]; ok || g.reserved[above] {
+			return false
+		}
+	}
+	return true
+}
+
+// below mengembalikan kontainer berbeda di bawah c pada semua tier di kolom c
+func (g *stackGrid) below(c *models.Container) []*models.Container {
+	var result []*models.Container
+	seen := make(map[*models.Container]bool)
+	for _, slot := range g.slots(c) {
+		for t := c.Tier - 1; t >= 1; t-- {
+			if other, ok := g.cells[[3]int{slot, c.Row, t}]; ok && other != c && !seen[other] {
+				seen[other] = true
+				result = append(result, other)
+			}
+		}
+	}
+	return result
+}
+
+func (g *stackGrid) mixedPairs(c *models.Container) int {
+	count := 0
+	for _, other := range g.below(c) {
+		if g.group(other) != g.group(c) {
+			count++
+		}
+	}
+	return count
+}
+
+func (g *stackGrid) inPlan(c *models.Container, slot, row, tier int) bool {
+	var plans []models.YardPlan
+	for _, p := range g.plans {
+		if p.PlannedSize == c.Size && p.PlannedHeight == c.Height && p.PlannedType == c.Type {
+			plans = append(plans, p)
+		}
+	}
+	return findCoveringPlan(plans, slot, row, tier, c.Size) != nil
+}
+
+func (g *stackGrid) misplacedCount() int {
+	count := 0
+	for _, c := range g.containers() {
+		if !g.inPlan(c, c.Slot, c.Row, c.Tier) {
+			count++
+		}
+	}
+	return count
+}
+
+func (g *stackGrid) rehandleCount() int {
+	count := 0
+	for _, c := range g.containers() {
+		count += g.mixedPairs(c)
+	}
+	return count
+}
+
+// containers mengembalikan setiap kontainer satu kali, urut berdasarkan posisi supaya hasil deterministik
+func (g *stackGrid) containers() []*models.Container {
+	var result []*models.Container
+	seen := make(map[*models.Container]bool)
+	for slot := 1; slot <= g.block.TotalSlot; slot++ {
+		for row := 1; row <= g.block.TotalRow; row++ {
+			for tier := 1; tier <= g.block.TotalTier; tier++ {
+				if c, ok := g.cells[[3]int{slot, row, tier}]; ok && !seen[c] {
+					seen[c] = true
+					result = append(result, c)
+				}
+			}
+		}
+	}
+	return result
+}
+
+// canPlace memeriksa posisi kosong dan tidak dipesan, dalam batas block dan tidak melayang (tier 1 atau semua cell
+// di bawah terisi)
+func (g *stackGrid) canPlace(size, slot, row, tier int) bool {
+	slots := []int{slot}
+	if size == 40 {
+		slots = append(slots, slot+1)
+	}
+	for _, s := range slots {
+		if s > g.block.TotalSlot || row > g.block.TotalRow || tier > g.block.TotalTier {
+			return false
+		}
+		if _, ok := g.cells[[3]int{s, row, tier}]; ok || g.reserved[[3]int{s, row, tier}] {
+			return false
+		}
+		if tier > 1 {
+			if _, ok := g.cells[[3]int{s, row, tier - 1}]; !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// bestMove mencari pemindahan kontainer paling atas dengan perbaikan skor terbesar
+func (g *stackGrid) bestMove() (housekeepingMove, bool) {
+	var best housekeepingMove
+	found := false
+
+	for _, c := range g.containers() {
+		if !g.isTop(c) {
+			continue
+		}
+		misplaced := !g.inPlan(c, c.Slot, c.Row, c.Tier)
+		mixed := g.mixedPairs(c)
+		if !misplaced && mixed == 0 {
+			continue
+		}
+
+		origSlot, origRow, origTier := c.Slot, c.Row, c.Tier
+		g.remove(c)
+		for slot := 1; slot <= g.block.TotalSlot; slot++ {
+			for row := 1; row <= g.block.TotalRow; row++ {
+				for tier := 1; tier <= g.block.TotalTier; tier++ {
+					if slot == origSlot && row == origRow && tier == origTier {
+						continue
+					}
+					if !g.inPlan(c, slot, row, tier) || !g.canPlace(c.Size, slot, row, tier) {
+						continue
+					}
+
+					c.Slot, c.Row, c.Tier = slot, row, tier
+					score := g.score(misplaced, mixed, c)
+					c.Slot, c.Row, c.Tier = origSlot, origRow, origTier
+
+					if score > 0 && (!found || score > best.score) {
+						reason := HousekeepingReasonMixedStack
+						if misplaced {
+							reason = HousekeepingReasonOutOfPlan
+						}
+						best = housekeepingMove{c: c, slot: slot, row: row, tier: tier, reason: reason, score: score}
+						found = true
+					}
+				}
+			}
+		}
+		g.place(c)
+	}
+	return best, found
+}
+
+// score menghitung perbaikan jika c (yang posisinya sudah diubah sementara) dipindahkan
+func (g *stackGrid) score(wasMisplaced bool, mixedBefore int, c *models.Container) int {
+	score := mixedBefore - g.mixedPairs(c)
+	if wasMisplaced {
+		score += misplacedWeight
+	}
+	return score
+}
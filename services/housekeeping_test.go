@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"yard-calculation/models"
+	"yard-calculation/schemas"
+)
+
+func TestHousekeepingReservedPositions(t *testing.T) {
+	ctx := context.Background()
+	reservation := func(number string, slot, row, tier int) models.Container {
+		return models.Container{ContainerNumber: number, YardID: "YRD1", BlockID: "A", Slot: slot, Row: row, Tier: tier, Size: 20, Height: 8.6, Type: "DRY"}
+	}
+
+	tests := []struct {
+		name     string
+		reserved staticReserver
+		want     []string
+	}{
+		{"no reservations", nil, []string{"MSKU0000001 A-5-1-1 -> A-1-1-1"}},
+		// 1-1-2 juga dilewati karena cell yang dipesan di bawahnya belum ditempati
+		{"target reserved", staticReserver{reservation("MSKU0000009", 1, 1, 1)}, []string{"MSKU0000001 A-5-1-1 -> A-1-2-1"}},
+		{"reserved above container", staticReserver{reservation("MSKU0000009", 5, 1, 2)}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _ := newTestContainerService(t)
+			service.AddReserver(tt.reserved)
+			// Kontainer 20ft di area rencana 40ft, disimpan langsung karena placement menolak posisi di luar rencana
+			misplaced := &models.Container{ContainerNumber: "MSKU0000001", Size: 20, Height: 8.6, Type: "DRY", YardID: "YRD1", BlockID: "A", Slot: 5, Row: 1, Tier: 1, IsPlaced: true}
+			if err := service.Repo.CreateContainer(ctx, misplaced); err != nil {
+				t.Fatal(err)
+			}
+
+			response, err := NewHousekeepingService(service).PlanHousekeeping(ctx, "YRD1", "A", schemas.HousekeepingRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, m := range response.Moves {
+				got = append(got, fmt.Sprintf("%s %s-%d-%d-%d -> %s-%d-%d-%d", m.ContainerNumber, m.From.Block, m.From.Slot, m.From.Row, m.From.Tier, m.To.Block, m.To.Slot, m.To.Row, m.To.Tier))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("moves = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
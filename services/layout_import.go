@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"yard-calculation/models"
+	"yard-calculation/repositories"
+	"yard-calculation/schemas"
+	"yard-calculation/utils"
+)
+
+// LayoutTables berisi tabel yard, block dan rencana yang akan diimport, tabel nil dilewati
+type LayoutTables struct {
+	Yards  *utils.Table
+	Blocks *utils.Table
+	Plans  *utils.Table
+}
+
+// ReadFile mengisi tabel dari file. XLSX dibaca per sheet bernama yards, blocks dan plans; XLSX dengan satu sheet
+// dan CSV diisi ke tabel sesuai kind.
+func (t *LayoutTables) ReadFile(kind, filename string, r io.Reader) error {
+	if utils.IsXLSX(filename) {
+		sheets, err := utils.ReadXLSXTables(r)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, name := range []string{"yards", "blocks", "plans"} {
+			if sheet, ok := sheets[name]; ok {
+				t.set(name, sheet)
+				found = true
+			}
+		}
+		if !found && len(sheets) == 1 {
+			for _, sheet := range sheets {
+				found = t.set(kind, sheet)
+			}
+		}
+		if !found {
+			return fmt.Errorf("xlsx file %s has no yards, blocks or plans sheet", filename)
+		}
+		return nil
+	}
+
+	table, err := utils.ReadCSVTable(r)
+	if err != nil {
+		return err
+	}
+	if !t.set(kind, table) {
+		return fmt.Errorf("csv file %s must be given as yards, blocks or plans", filename)
+	}
+	return nil
+}
+
+// set mengisi tabel sesuai kind, false jika kind bukan yards, blocks atau plans
+func (t *LayoutTables) set(kind string, table *utils.Table) bool {
+	switch kind {
+	case "yards":
+		t.Yards = table
+	case "blocks":
+		t.Blocks = table
+	case "plans":
+		t.Plans = table
+	default:
+		return false
+	}
+	return true
+}
+
+type LayoutImportService struct {
+	Repo repositories.Store
+}
+
+func NewLayoutImportService(repo repositories.Store) *LayoutImportService {
+	return &LayoutImportService{Repo: repo}
+}
+
+// ImportLayout memvalidasi seluruh isi file terhadap data yang sudah ada, lalu menyimpannya
+// hanya jika tidak ada error sama sekali dan dryRun bernilai false.
+func (s *LayoutImportService) ImportLayout(ctx context.Context, tables LayoutTables, dryRun bool) (*schemas.ImportLayoutResponse, error) {
+	existingYards, err := s.Repo.GetAllYards(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	v := newLayoutValidator(existingYards)
+	yards := v.parseYards(tables.Yards)
+	blocks := v.parseBlocks(tables.Blocks)
+	plans := v.parsePlans(tables.Plans)
+
+	response := schemas.ImportLayoutResponse{
+		DryRun: dryRun,
+		Valid:  len(v.errors) == 0,
+		Yards:  len(yards),
+		Blocks: len(blocks),
+		Plans:  len(plans),
+		Errors: v.errors,
+	}
+	if !response.Valid || dryRun {
+		return &response, nil
+	}
+
+	if err := s.Repo.ImportLayout(ctx, yards, blocks, plans); err != nil {
+		return nil, err
+	}
+	response.Committed = true
+	return &response, nil
+}
+
+type layoutValidator struct {
+	yards  map[string]bool
+	blocks map[string]models.Block
+	plans  map[string][]models.YardPlan // Key: block ID
+	errors []schemas.ImportRowError
+}
+
+func newLayoutValidator(existing []models.Yard) *layoutValidator {
+	v := &layoutValidator{
+		yards:  make(map[string]bool),
+		blocks: make(map[string]models.Block),
+		plans:  make(map[string][]models.YardPlan),
+		errors: []schemas.ImportRowError{},
+	}
+	for _, yard := range existing {
+		v.yards[yard.ID] = true
+		for _, block := range yard.Blocks {
+			v.blocks[block.ID] = block
+			v.plans[block.ID] = append(v.plans[block.ID], block.Plans...)
+		}
+	}
+	return v
+}
+
+func (v *layoutValidator) addError(sheet string, row int, format string, args ...any) {
+	v.errors = append(v.errors, schemas.ImportRowError{Sheet: sheet, Row: row, Message: fmt.Sprintf(format, args...)})
+}
+
+// parseInt membaca kolom integer, mencatat error dan mengembalikan false jika tidak valid
+func (v *layoutValidator) parseInt(t *utils.Table, record []string, sheet string, row int, column string, out *int) bool {
+	n, err := strconv.Atoi(t.Get(record, column))
+	if err != nil {
+		v.addError(sheet, row, "%s must be an integer", column)
+		return false
+	}
+	*out = n
+	return true
+}
+
+func (v *layoutValidator) parseYards(t *utils.Table) []models.Yard {
+	var yards []models.Yard
+	if t == nil {
+		return yards
+	}
+	for i, record := range t.Rows {
+		row := i + 2
+		yard := models.Yard{ID: t.Get(record, "id"), Name: t.Get(record, "name")}
+		if yard.ID == "" {
+			v.addError("yards", row, "id is required")
+			continue
+		}
+		if v.yards[yard.ID] {
+			v.addError("yards", row, "yard %s already exists", yard.ID)
+			continue
+		}
+		v.yards[yard.ID] = true
+		yards = append(yards, yard)
+	}
+	return yards
+}
+
+func (v *layoutValidator) parseBlocks(t *utils.Table) []models.Block {
+	var blocks []models.Block
+	if t == nil {
+		return blocks
+	}
+	for i, record := range t.Rows {
+		row := i + 2
+		block := models.Block{ID: t.Get(record, "id"), Name: t.Get(record, "name"), YardID: t.Get(record, "yard_id")}
+		valid := true
+		if block.ID == "" {
+			v.addError("blocks", row, "id is required")
+			valid = false
+		} else if _, exists := v.blocks[block.ID]; exists {
+			v.addError("blocks", row, "block %s already exists", block.ID)
+			valid = false
+		}
+		if !v.yards[block.YardID] {
+			v.addError("blocks", row, "yard %s not found", block.YardID)
+			valid = false
+		}
+		valid = v.parseInt(t, record, "blocks", row, "total_slot", &block.TotalSlot) && valid
+		valid = v.parseInt(t, record, "blocks", row, "total_row", &block.TotalRow) && valid
+		valid = v.parseInt(t, record, "blocks", row, "total_tier", &block.TotalTier) && valid
+		if !valid {
+			continue
+		}
+		if block.TotalSlot < 1 || block.TotalRow < 1 || block.TotalTier < 1 {
+			v.addError("blocks", row, "total_slot, total_row and total_tier must be at least 1")
+			continue
+		}
+		v.blocks[block.ID] = block
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+func (v *layoutValidator) parsePlans(t *utils.Table) []models.YardPlan {
+	var plans []models.YardPlan
+	if t == nil {
+		return plans
+	}
+	for i, record := range t.Rows {
+		row := i + 2
+		plan := models.YardPlan{YardID: t.Get(record, "yard_id"), BlockID: t.Get(record, "block_id"), PlannedType: t.Get(record, "planned_type")}
+
+		valid := true
+		block, exists := v.blocks[plan.BlockID]
+		if !exists || block.YardID != plan.YardID {
+			v.addError("plans", row, "block %s in yard %s not found", plan.BlockID, plan.YardID)
+			valid = false
+		}
+		valid = v.parseInt(t, record, "plans", row, "planned_size", &plan.PlannedSize) && valid
+		height, err := strconv.ParseFloat(t.Get(record, "planned_height"), 64)
+		if err != nil || height <= 0 {
+			v.addError("plans", row, "planned_height must be a positive number")
+			valid = false
+		}
+		plan.PlannedHeight = height
+		if plan.PlannedType == "" {
+			v.addError("plans", row, "planned_type is required")
+			valid = false
+		}
+		valid = v.parseInt(t, record, "plans", row, "min_slot", &plan.MinSlot) && valid
+		valid = v.parseInt(t, record, "plans", row, "max_slot", &plan.MaxSlot) && valid
+		valid = v.parseInt(t, record, "plans", row, "min_row", &plan.MinRow) && valid
+		valid = v.parseInt(t, record, "plans", row, "max_row", &plan.MaxRow) && valid
+		valid = v.parseInt(t, record, "plans", row, "min_tier", &plan.MinTier) && valid
+		valid = v.parseInt(t, record, "plans", row, "max_tier", &plan.MaxTier) && valid
+		if !valid {
+			continue
+		}
+
+		if plan.PlannedSize != 20 && plan.PlannedSize != 40 {
+			v.addError("plans", row, "planned_size must be 20 or 40")
+			continue
+		}
+		if plan.MinSlot < 1 || plan.MinRow < 1 || plan.MinTier < 1 ||
+			plan.MinSlot > plan.MaxSlot || plan.MinRow > plan.MaxRow || plan.MinTier > plan.MaxTier {
+			v.addError("plans", row, "min values must be at least 1 and not greater than max values")
+			continue
+		}
+		if plan.MaxSlot > block.TotalSlot || plan.MaxRow > block.TotalRow || plan.MaxTier > block.TotalTier {
+			v.addError("plans", row, "plan area exceeds block %s bounds (%d slot x %d row x %d tier)", block.ID, block.TotalSlot, block.TotalRow, block.TotalTier)
+			continue
+		}
+		if plan.PlannedSize == 40 && plan.MaxSlot-plan.MinSlot < 1 {
+			v.addError("plans", row, "40ft plan needs at least two slots")
+			continue
+		}
+		if other := findOverlappingPlan(v.plans[plan.BlockID], plan); other != nil {
+			if other.ID != 0 {
+				v.addError("plans", row, "plan area overlaps existing plan %d in block %s", other.ID, plan.BlockID)
+			} else {
+				v.addError("plans", row, "plan area overlaps another plan in this file for block %s", plan.BlockID)
+			}
+			continue
+		}
+
+		v.plans[plan.BlockID] = append(v.plans[plan.BlockID], plan)
+		plans = append(plans, plan)
+	}
+	return plans
+}
+
+func findOverlappingPlan(plans []models.YardPlan, plan models.YardPlan) *models.YardPlan {
+	for i, p := range plans {
+		if p.MinSlot <= plan.MaxSlot && plan.MinSlot <= p.MaxSlot &&
+			p.MinRow <= plan.MaxRow && plan.MinRow <= p.MaxRow &&
+			p.MinTier <= plan.MaxTier && plan.MinTier <= p.MaxTier {
+			return &plans[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"context"
+	"yard-calculation/metrics"
+	"yard-calculation/models"
+)
+
+// MetricsListener menghitung penempatan dan pickup per yard dan block untuk /metrics dan menandai gauge occupancy
+// perlu dihitung ulang (Occupancy opsional)
+type MetricsListener struct {
+	Occupancy *metrics.OccupancyCollector
+}
+
+// HandleContainerEvent memenuhi ContainerEventListener
+func (l MetricsListener) HandleContainerEvent(_ context.Context, eventType string, container *models.Container) {
+	switch eventType {
+	case ContainerEventPlaced:
+		metrics.RecordPlacement(container.YardID, container.BlockID)
+	case ContainerEventPickedUp:
+		metrics.RecordPickup(container.YardID, container.BlockID)
+	}
+	if l.Occupancy != nil {
+		l.Occupancy.Invalidate()
+	}
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+	"yard-calculation/auth"
+	"yard-calculation/edifact"
+	"yard-calculation/models"
+	"yard-calculation/repositories"
+	"yard-calculation/schemas"
+)
+
+// PreAdviceService mengelola booking kedatangan truk. Posisi yard dihitung dan dipesan saat booking dibuat
+// (PositionReserver), penempatan saat gate-in harus sesuai dengan posisi tersebut (PlacementValidator), dan
+// booking ditutup setelah kontainer ditempatkan (ContainerEventListener).
+type PreAdviceService struct {
+	Repo             *repositories.PreAdviceRepository
+	ContainerService *ContainerService
+	// Lama posisi tetap dipesan setelah jendela kedatangan lewat
+	Grace time.Duration
+}
+
+func NewPreAdviceService(repo *repositories.PreAdviceRepository, containerService *ContainerService, grace time.Duration) *PreAdviceService {
+	return &PreAdviceService{Repo: repo, ContainerService: containerService, Grace: grace}
+}
+
+// CreateBooking membuat pre-advice dan memesan posisi yard untuk kontainer
+func (s *PreAdviceService) CreateBooking(ctx context.Context, req schemas.CreatePreAdviceRequest) (*models.PreAdvice, error) {
+	existing, err := s.Repo.GetOpenPreAdvice(ctx, req.Yard, req.ContainerNumber)
+	if err != nil && err.Error() != fmt.Sprintf("pre-advice for container %s in yard %s not found", req.ContainerNumber, req.Yard) {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("container %s already has open pre-advice %d in yard %s", req.ContainerNumber, existing.ID, req.Yard)
+	}
+
+	placed, err := s.ContainerService.Repo.GetContainerByNumber(ctx, req.ContainerNumber)
+	if err != nil && err.Error() != fmt.Sprintf("container with number %s not found or not placed", req.ContainerNumber) {
+		return nil, err
+	}
+	if placed != nil {
+		return nil, fmt.Errorf("container with number %s is already placed at %s-%d-%d-%d", req.ContainerNumber, placed.BlockID, placed.Slot, placed.Row, placed.Tier)
+	}
+
+	suggestion, err := s.ContainerService.GetSuggestedPosition(ctx, req.Yard, req.ContainerNumber, req.ContainerSize, req.ContainerHeight, req.ContainerType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Kode ISO hanya pelengkap, spesifikasi di luar tabel tetap bisa dibooking
+	sizeType, _ := edifact.FormatSizeType(req.ContainerSize, req.ContainerHeight, req.ContainerType)
+
+	expectedFrom, expectedUntil := req.ExpectedFrom, req.ExpectedUntil
+	preAdvice := &models.PreAdvice{
+		ContainerNumber: req.ContainerNumber,
+		YardID:          req.Yard,
+		Size:            req.ContainerSize,
+		Height:          req.ContainerHeight,
+		Type:            req.ContainerType,
+		SizeType:        sizeType,
+		LineOperator:    req.LineOperator,
+		Status:          models.PreAdviceStatusPending,
+		Source:          "BOOKING",
+		ExpectedFrom:    &expectedFrom,
+		ExpectedUntil:   &expectedUntil,
+		BlockID:         suggestion.Block,
+		Slot:            suggestion.Slot,
+		Row:             suggestion.Row,
+		Tier:            suggestion.Tier,
+	}
+	if err := s.Repo.CreatePreAdvice(ctx, preAdvice); err != nil {
+		return nil, err
+	}
+	return preAdvice, nil
+}
+
+func (s *PreAdviceService) GetPreAdvice(ctx context.Context, id uint) (*models.PreAdvice, error) {
+	return s.Repo.GetPreAdvice(ctx, id)
+}
+
+func (s *PreAdviceService) ListPreAdvices(ctx context.Context, req schemas.ListPreAdviceRequest) ([]models.PreAdvice, error) {
+	filter := repositories.PreAdviceFilter{YardID: req.Yard, Status: req.Status}
+	if req.From != "" {
+		from, err := time.Parse(time.DateOnly, req.From)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from date %s, expected format YYYY-MM-DD", req.From)
+		}
+		filter.ExpectedFrom = &from
+	}
+	if req.To != "" {
+		to, err := time.Parse(time.DateOnly, req.To)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to date %s, expected format YYYY-MM-DD", req.To)
+		}
+		// Tanggal akhir inklusif, jadi batas atas adalah awal hari berikutnya
+		until := to.AddDate(0, 0, 1)
+		filter.ExpectedUntil = &until
+	}
+	return s.Repo.ListPreAdvices(ctx, filter)
+}
+
+// CancelPreAdvice membatalkan booking yang belum ditempatkan, posisi yang dipesan ikut dilepas
+func (s *PreAdviceService) CancelPreAdvice(ctx context.Context, id uint) (*models.PreAdvice, error) {
+	preAdvice, err := s.Repo.GetPreAdvice(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := auth.CheckYard(ctx, preAdvice.YardID); err != nil {
+		return nil, err
+	}
+	if preAdvice.Status != models.PreAdviceStatusPending && preAdvice.Status != models.PreAdviceStatusArrived {
+		return nil, fmt.Errorf("pre-advice %d cannot be cancelled from status %s", id, preAdvice.Status)
+	}
+
+	preAdvice.Status = models.PreAdviceStatusCancelled
+	if err := s.Repo.UpdatePreAdvice(ctx, preAdvice); err != nil {
+		return nil, err
+	}
+	return preAdvice, nil
+}
+
+// ReservedPositions memenuhi PositionReserver
+func (s *PreAdviceService) ReservedPositions(ctx context.Context, yardID string) ([]models.Container, error) {
+	preAdvices, err := s.Repo.GetReservingPreAdvices(ctx, yardID, time.Now().Add(-s.Grace))
+	if err != nil {
+		return nil, err
+	}
+	reserved := make([]models.Container, 0, len(preAdvices))
+	for _, p := range preAdvices {
+		reserved = append(reserved, models.Container{
+			ContainerNumber: p.ContainerNumber,
+			YardID:          p.YardID,
+			BlockID:         p.BlockID,
+			Slot:            p.Slot,
+			Row:             p.Row,
+			Tier:            p.Tier,
+			Size:            p.Size,
+			Height:          p.Height,
+			Type:            p.Type,
+		})
+	}
+	return reserved, nil
+}
+
+// CheckPlacement memenuhi PlacementValidator: kontainer dengan booking yang masih berlaku harus ditempatkan di
+// posisi yang dipesan dan spesifikasinya harus sama dengan booking
+func (s *PreAdviceService) CheckPlacement(ctx context.Context, container *models.Container) error {
+	preAdvice, err := s.Repo.GetOpenPreAdvice(ctx, container.YardID, container.ContainerNumber)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("pre-advice for container %s in yard %s not found", container.ContainerNumber, container.YardID) {
+			return nil
+		}
+		return err
+	}
+	if !s.holdsPosition(preAdvice) {
+		return nil
+	}
+
+	if container.Size != preAdvice.Size || container.Height != preAdvice.Height || container.Type != preAdvice.Type {
+		return fmt.Errorf("container %s spec (size: %d, height: %.1f, type: %s) does not match pre-advice %d (size: %d, height: %.1f, type: %s)",
+			container.ContainerNumber, container.Size, container.Height, container.Type, preAdvice.ID, preAdvice.Size, preAdvice.Height, preAdvice.Type)
+	}
+	if container.BlockID != preAdvice.BlockID || container.Slot != preAdvice.Slot || container.Row != preAdvice.Row || container.Tier != preAdvice.Tier {
+		return fmt.Errorf("container %s is booked for position %s-%d-%d-%d by pre-advice %d", container.ContainerNumber, preAdvice.BlockID, preAdvice.Slot, preAdvice.Row, preAdvice.Tier, preAdvice.ID)
+	}
+	return nil
+}
+
+// ApplyPlacementDefaults memenuhi PlacementDefaulter: status full/empty dari gate-in dipakai jika request penempatan
+// tidak mengirim cargo_status
+func (s *PreAdviceService) ApplyPlacementDefaults(ctx context.Context, container *models.Container) error {
+	if container.CargoStatus != "" {
+		return nil
+	}
+	preAdvice, err := s.Repo.GetOpenPreAdvice(ctx, container.YardID, container.ContainerNumber)
+	if err != nil {
+		if err.Error() == fmt.Sprintf("pre-advice for container %s in yard %s not found", container.ContainerNumber, container.YardID) {
+			return nil
+		}
+		return err
+	}
+	container.CargoStatus = preAdvice.CargoStatus
+	return nil
+}
+
+// HandleContainerEvent memenuhi ContainerEventListener, pre-advice ditutup setelah kontainer ditempatkan
+func (s *PreAdviceService) HandleContainerEvent(ctx context.Context, eventType string, container *models.Container) {
+	if eventType != ContainerEventPlaced {
+		return
+	}
+	preAdvice, err := s.Repo.GetOpenPreAdvice(ctx, container.YardID, container.ContainerNumber)
+	if err != nil {
+		if err.Error() != fmt.Sprintf("pre-advice for container %s in yard %s not found", container.ContainerNumber, container.YardID) {
+			slog.ErrorContext(ctx, "error loading pre-advice", "error", err)
+		}
+		return
+	}
+
+	now := time.Now()
+	if preAdvice.ArrivedAt == nil {
+		preAdvice.ArrivedAt = &now
+	}
+	preAdvice.PlacedAt = &now
+	preAdvice.Status = models.PreAdviceStatusPlaced
+	if err := s.Repo.UpdatePreAdvice(ctx, preAdvice); err != nil {
+		slog.ErrorContext(ctx, "error closing pre-advice", "pre_advice_id", preAdvice.ID, "error", err)
+	}
+}
+
+// holdsPosition sama dengan kriteria GetReservingPreAdvices
+func (s *PreAdviceService) holdsPosition(preAdvice *models.PreAdvice) bool {
+	if preAdvice.BlockID == "" {
+		return false
+	}
+	if preAdvice.Status == models.PreAdviceStatusArrived {
+		return true
+	}
+	return preAdvice.ExpectedUntil == nil || !preAdvice.ExpectedUntil.Add(s.Grace).Before(time.Now())
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"yard-calculation/metrics"
+	"yard-calculation/models"
+	"yard-calculation/repositories"
+	"yard-calculation/schemas"
+)
+
+type StatisticsService struct {
+	Repo repositories.Store
+}
+
+func NewStatisticsService(repo repositories.Store) *StatisticsService {
+	return &StatisticsService{Repo: repo}
+}
+
+func (s *StatisticsService) GetYardStatistics(ctx context.Context, yardName string) (*schemas.YardStatistics, error) {
+	yard, err := s.Repo.GetYardByName(ctx, yardName)
+	if err != nil {
+		return nil, err
+	}
+
+	response := schemas.YardStatistics{
+		Yard:   yard.ID,
+		Blocks: []schemas.BlockStatistics{},
+		Specs:  []schemas.SpecCapacity{},
+	}
+
+	// Cell kosong per spesifikasi, pakai set supaya plan yang overlap tidak dihitung dua kali
+	type specKey struct {
+		size   int
+		height float64
+		ctype  string
+	}
+	specCells := make(map[specKey]map[string]bool)
+	var specOrder []specKey
+
+	for _, block := range yard.Blocks {
+		if err := s.Repo.LoadBlockOccupancy(ctx, &block); err != nil {
+			return nil, fmt.Errorf("error loading occupancy for block %s: %v", block.ID, err)
+		}
+
+		blockStats := s.blockStatistics(&block)
+		response.TEUCapacity += blockStats.TEUCapacity
+		response.TEUOccupied += blockStats.TEUOccupied
+		response.Blocks = append(response.Blocks, blockStats)
+
+		for _, plan := range block.Plans {
+			key := specKey{plan.PlannedSize, plan.PlannedHeight, plan.PlannedType}
+			if _, exists := specCells[key]; !exists {
+				specCells[key] = make(map[string]bool)
+				specOrder = append(specOrder, key)
+			}
+			forEachPlanPosition(&block, plan, func(slot, row, tier int) {
+				if s.Repo.IsPositionAvailable(&block, slot, row, tier) {
+					specCells[key][fmt.Sprintf("%s-%d-%d-%d", block.ID, slot, row, tier)] = true
+				}
+			})
+		}
+	}
+	response.Utilization = percent(response.TEUOccupied, response.TEUCapacity)
+
+	for _, key := range specOrder {
+		response.Specs = append(response.Specs, schemas.SpecCapacity{
+			Size:         key.size,
+			Height:       key.height,
+			Type:         key.ctype,
+			FreePosition: len(specCells[key]),
+		})
+	}
+
+	return &response, nil
+}
+
+// BlockOccupancies menghitung posisi terisi dan kapasitas setiap block di semua yard, dipakai gauge occupancy di /metrics
+func (s *StatisticsService) BlockOccupancies(ctx context.Context) ([]metrics.BlockOccupancy, error) {
+	yards, err := s.Repo.GetAllYards(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	occupancies := []metrics.BlockOccupancy{}
+	for _, yard := range yards {
+		for _, block := range yard.Blocks {
+			if err := s.Repo.LoadBlockOccupancy(ctx, &block); err != nil {
+				return nil, fmt.Errorf("error loading occupancy for block %s: %v", block.ID, err)
+			}
+			stats := s.blockStatistics(&block)
+			occupancies = append(occupancies, metrics.BlockOccupancy{
+				Yard:     yard.ID,
+				Block:    block.ID,
+				Occupied: stats.TEUOccupied,
+				Capacity: stats.TEUCapacity,
+			})
+		}
+	}
+	return occupancies, nil
+}
+
+// blockStatistics menghitung kapasitas block, Occupancy harus sudah dimuat
+func (s *StatisticsService) blockStatistics(block *models.Block) schemas.BlockStatistics {
+	stats := schemas.BlockStatistics{
+		Block:       block.ID,
+		TEUCapacity: block.TotalSlot * block.TotalRow * block.TotalTier,
+		Plans:       []schemas.PlanCapacity{},
+	}
+	for slot := 1; slot <= block.TotalSlot; slot++ {
+		for row := 1; row <= block.TotalRow; row++ {
+			for tier := 1; tier <= block.TotalTier; tier++ {
+				if !s.Repo.IsPositionAvailable(block, slot, row, tier) {
+					stats.TEUOccupied++
+				}
+			}
+		}
+	}
+	stats.Utilization = percent(stats.TEUOccupied, stats.TEUCapacity)
+
+	for _, plan := range block.Plans {
+		stats.Plans = append(stats.Plans, s.PlanCapacity(block, plan))
+	}
+	return stats
+}
+
+// PlanCapacity menghitung sisa kapasitas satu rencana, Occupancy block harus sudah dimuat
+func (s *StatisticsService) PlanCapacity(block *models.Block, plan models.YardPlan) schemas.PlanCapacity {
+	capacity := schemas.PlanCapacity{
+		PlanID:        plan.ID,
+		BlockID:       block.ID,
+		PlannedSize:   plan.PlannedSize,
+		PlannedHeight: plan.PlannedHeight,
+		PlannedType:   plan.PlannedType,
+	}
+
+	forEachPlanPosition(block, plan, func(slot, row, tier int) {
+		capacity.TotalPosition++
+		if s.Repo.IsPositionAvailable(block, slot, row, tier) {
+			capacity.FreePosition++
+		}
+	})
+	capacity.FillPercent = percent(capacity.TotalPosition-capacity.FreePosition, capacity.TotalPosition)
+
+	// Sisa kapasitas hanya dilaporkan untuk ukuran rencana, kontainer ukuran lain tidak boleh masuk area ini
+	switch plan.PlannedSize {
+	case 20:
+		capacity.Remaining20ft = capacity.FreePosition
+	case 40:
+		// 40ft butuh dua slot berurutan, hitung secara greedy dari slot terendah seperti FindSuggestedPosition
+		for t := plan.MinTier; t <= plan.MaxTier && t <= block.TotalTier; t++ {
+			for r := plan.MinRow; r <= plan.MaxRow && r <= block.TotalRow; r++ {
+				for slot := plan.MinSlot; slot+1 <= plan.MaxSlot && slot+1 <= block.TotalSlot; slot++ {
+					if s.Repo.IsPositionAvailable40ft(block, slot, r, t) {
+						capacity.Remaining40ft++
+						slot++
+					}
+				}
+			}
+		}
+	}
+
+	return capacity
+}
+
+// forEachPlanPosition memanggil fn untuk setiap posisi di area rencana yang masih dalam batas block
+func forEachPlanPosition(block *models.Block, plan models.YardPlan, fn func(slot, row, tier int)) {
+	for t := plan.MinTier; t <= plan.MaxTier && t <= block.TotalTier; t++ {
+		for r := plan.MinRow; r <= plan.MaxRow && r <= block.TotalRow; r++ {
+			for slot := plan.MinSlot; slot <= plan.MaxSlot && slot <= block.TotalSlot; slot++ {
+				fn(slot, r, t)
+			}
+		}
+	}
+}
+
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPlanCapacityReportsPlannedSize(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newTestContainerService(t)
+	yard, err := service.Repo.GetYardByName(ctx, "YRD1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	block := &yard.Blocks[0]
+	if err := service.Repo.LoadBlockOccupancy(ctx, block); err != nil {
+		t.Fatal(err)
+	}
+
+	statistics := NewStatisticsService(service.Repo)
+	want := map[int][2]int{
+		20: {12, 0}, // Slot 1-2, 2 row, 3 tier
+		40: {0, 6},  // Slot 3-4 per row dan tier, slot 5 tidak cukup untuk 40ft
+	}
+	if len(block.Plans) != 2 {
+		t.Fatalf("block A has %d plans, want 2", len(block.Plans))
+	}
+	for _, plan := range block.Plans {
+		capacity := statistics.PlanCapacity(block, plan)
+		if got := [2]int{capacity.Remaining20ft, capacity.Remaining40ft}; got != want[plan.PlannedSize] {
+			t.Errorf("%dft plan remaining 20ft/40ft = %v, want %v", plan.PlannedSize, got, want[plan.PlannedSize])
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"yard-calculation/edifact"
+	"yard-calculation/schemas"
+)
+
+type VesselService struct {
+	ContainerService *ContainerService
+}
+
+func NewVesselService(containerService *ContainerService) *VesselService {
+	return &VesselService{ContainerService: containerService}
+}
+
+// PlanDischarge membaca BAPLIE/COARRI dan mengalokasikan posisi yard untuk semua kontainer yang dibongkar
+// di port sekaligus lewat SuggestBatch. Kontainer tanpa POD hanya ikut jika includeNoPOD bernilai true.
+// Tidak ada data yang disimpan, hasilnya hanya rencana.
+func (s *VesselService) PlanDischarge(ctx context.Context, yardName, port string, includeNoPOD bool, data string) (*schemas.DischargePlanResponse, error) {
+	interchange, err := edifact.ParseInterchange(data)
+	if err != nil {
+		return nil, err
+	}
+
+	response := schemas.DischargePlanResponse{
+		Containers: []schemas.DischargeAllocation{},
+		Errors:     []edifact.SegmentError{},
+	}
+
+	var items []schemas.BatchSuggestItem
+	var itemIndex []int // Index allocation di response.Containers untuk setiap item batch
+	for _, msg := range interchange.Messages {
+		info, containers, errs := edifact.ParseVesselMessage(msg)
+		response.Errors = append(response.Errors, errs...)
+		if info != nil && info.Voyage != "" {
+			response.Voyage = info.Voyage
+			response.VesselID = info.VesselID
+			response.VesselName = info.VesselName
+		}
+
+		for _, c := range containers {
+			// Kontainer dengan POD lain tetap di kapal
+			if c.POD != port && (c.POD != "" || !includeNoPOD) {
+				continue
+			}
+
+			response.Containers = append(response.Containers, schemas.DischargeAllocation{
+				ContainerNumber: c.ContainerNumber,
+				SizeType:        c.SizeType,
+				WeightKg:        c.WeightKg,
+				POD:             c.POD,
+				Stowage:         c.Stowage,
+			})
+
+			sizeType, err := edifact.ParseSizeType(c.SizeType)
+			if err != nil {
+				response.Containers[len(response.Containers)-1].Error = err.Error()
+				continue
+			}
+			items = append(items, schemas.BatchSuggestItem{
+				ContainerNumber: c.ContainerNumber,
+				ContainerSize:   sizeType.Size,
+				ContainerHeight: sizeType.Height,
+				ContainerType:   sizeType.Type,
+			})
+			itemIndex = append(itemIndex, len(response.Containers)-1)
+		}
+	}
+	response.Total = len(response.Containers)
+
+	if len(items) > 0 {
+		batch, err := s.ContainerService.SuggestBatch(ctx, yardName, items)
+		if err != nil {
+			return nil, err
+		}
+		for i, result := range batch.Results {
+			allocation := &response.Containers[itemIndex[i]]
+			allocation.SuggestedPosition = result.SuggestedPosition
+			allocation.Error = result.Error
+		}
+	}
+
+	for _, allocation := range response.Containers {
+		if allocation.SuggestedPosition != nil {
+			response.Allocated++
+		} else {
+			response.Unallocated++
+		}
+	}
+	return &response, nil
+}
@@ -0,0 +1,343 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+	"yard-calculation/auth"
+	"yard-calculation/logging"
+	"yard-calculation/models"
+	"yard-calculation/repositories"
+	"yard-calculation/schemas"
+)
+
+// WorkQueueService membuat job untuk alat di block dan menyimpan perubahan posisi kontainer saat job
+// dikonfirmasi selesai. Posisi tujuan job yang belum selesai dipesan lewat PositionReserver.
+type WorkQueueService struct {
+	Repo             *repositories.WorkJobRepository
+	EquipmentRepo    *repositories.EquipmentRepository
+	ContainerService *ContainerService
+}
+
+func NewWorkQueueService(repo *repositories.WorkJobRepository, equipmentRepo *repositories.EquipmentRepository, containerService *ContainerService) *WorkQueueService {
+	return &WorkQueueService{Repo: repo, EquipmentRepo: equipmentRepo, ContainerService: containerService}
+}
+
+// ReservedPositions memenuhi PositionReserver
+func (s *WorkQueueService) ReservedPositions(ctx context.Context, yardID string) ([]models.Container, error) {
+	jobs, err := s.Repo.GetOpenJobsWithTarget(ctx, yardID)
+	if err != nil {
+		return nil, err
+	}
+	reserved := make([]models.Container, 0, len(jobs))
+	for _, job := range jobs {
+		reserved = append(reserved, models.Container{
+			ContainerNumber: job.ContainerNumber,
+			YardID:          job.YardID,
+			BlockID:         job.ToBlockID,
+			Slot:            job.ToSlot,
+			Row:             job.ToRow,
+			Tier:            job.ToTier,
+			Size:            job.Size,
+			Height:          job.Height,
+			Type:            job.Type,
+		})
+	}
+	return reserved, nil
+}
+
+func (s *WorkQueueService) CreateEquipment(ctx context.Context, req schemas.CreateEquipmentRequest) (*models.Equipment, error) {
+	// Pastikan block ada di yard
+	if _, err := s.ContainerService.Repo.GetBlockByName(ctx, req.Block, req.Yard); err != nil {
+		return nil, err
+	}
+	equipment := &models.Equipment{
+		ID:      req.ID,
+		Name:    req.Name,
+		Kind:    req.Kind,
+		YardID:  req.Yard,
+		BlockID: req.Block,
+	}
+	if err := s.EquipmentRepo.CreateEquipment(ctx, equipment); err != nil {
+		return nil, err
+	}
+	return equipment, nil
+}
+
+func (s *WorkQueueService) ListEquipment(ctx context.Context, yardName string) ([]models.Equipment, error) {
+	return s.EquipmentRepo.ListEquipment(ctx, yardName)
+}
+
+func (s *WorkQueueService) ListJobs(ctx context.Context, req schemas.ListJobsRequest) ([]models.WorkJob, error) {
+	return s.Repo.ListJobs(ctx, repositories.JobFilter{YardID: req.Yard, EquipmentID: req.Equipment, Status: req.Status})
+}
+
+func (s *WorkQueueService) GetJob(ctx context.Context, id uint) (*models.WorkJob, error) {
+	return s.Repo.GetJob(ctx, id)
+}
+
+// CreatePlaceJob memvalidasi penempatan seperti PlaceContainerDetailed lalu membuat job PLACE
+func (s *WorkQueueService) CreatePlaceJob(ctx context.Context, yardName, containerNumber, blockName string, slot, row, tier int, size int, height float64, ctype, lineOperator, cargoStatus string) (*models.WorkJob, error) {
+	if err := s.checkNoOpenJob(ctx, containerNumber); err != nil {
+		return nil, err
+	}
+	container, err := s.ContainerService.ValidatePlacement(ctx, yardName, containerNumber, blockName, slot, row, tier, size, height, ctype, lineOperator, cargoStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	job := jobForContainer(models.JobTypePlace, container)
+	job.ToBlockID, job.ToSlot, job.ToRow, job.ToTier = container.BlockID, container.Slot, container.Row, container.Tier
+	if err := s.enqueue(ctx, job, job.ToBlockID); err != nil {
+		return nil, err
+	}
+	slog.InfoContext(jobLogContext(ctx, job), "job queued", "job_id", job.ID, "job_type", job.JobType, "equipment_id", job.EquipmentID)
+	return job, nil
+}
+
+// CreatePickupJob membuat job PICKUP untuk kontainer yang ada di lapangan
+func (s *WorkQueueService) CreatePickupJob(ctx context.Context, yardName, containerNumber string) (*models.WorkJob, error) {
+	if err := s.checkNoOpenJob(ctx, containerNumber); err != nil {
+		return nil, err
+	}
+	container, err := s.ContainerService.ValidatePickup(ctx, yardName, containerNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	job := jobForContainer(models.JobTypePickup, container)
+	job.FromBlockID, job.FromSlot, job.FromRow, job.FromTier = container.BlockID, container.Slot, container.Row, container.Tier
+	if err := s.enqueue(ctx, job, job.FromBlockID); err != nil {
+		return nil, err
+	}
+	slog.InfoContext(jobLogContext(ctx, job), "job queued", "job_id", job.ID, "job_type", job.JobType, "equipment_id", job.EquipmentID)
+	return job, nil
+}
+
+// CreateShiftJob membuat job SHIFT untuk memindahkan kontainer ke posisi lain, job diberikan ke alat di block asal
+func (s *WorkQueueService) CreateShiftJob(ctx context.Context, req schemas.ShiftContainerRequest) (*models.WorkJob, error) {
+	if err := s.checkNoOpenJob(ctx, req.ContainerNumber); err != nil {
+		return nil, err
+	}
+	// Posisi asal diambil sebelum ValidateShift mengubah posisi kontainer
+	current, err := s.ContainerService.GetPlacedContainer(ctx, req.Yard, req.ContainerNumber)
+	if err != nil {
+		return nil, err
+	}
+	fromBlock, fromSlot, fromRow, fromTier := current.BlockID, current.Slot, current.Row, current.Tier
+
+	container, err := s.ContainerService.ValidateShift(ctx, req.Yard, req.ContainerNumber, req.Block, req.Slot, req.Row, req.Tier)
+	if err != nil {
+		return nil, err
+	}
+
+	job := jobForContainer(models.JobTypeShift, container)
+	job.FromBlockID, job.FromSlot, job.FromRow, job.FromTier = fromBlock, fromSlot, fromRow, fromTier
+	job.ToBlockID, job.ToSlot, job.ToRow, job.ToTier = container.BlockID, container.Slot, container.Row, container.Tier
+	if err := s.enqueue(ctx, job, job.FromBlockID); err != nil {
+		return nil, err
+	}
+	slog.InfoContext(jobLogContext(ctx, job), "job queued", "job_id", job.ID, "job_type", job.JobType, "equipment_id", job.EquipmentID)
+	return job, nil
+}
+
+// StartJob menandai job sedang dikerjakan operator
+func (s *WorkQueueService) StartJob(ctx context.Context, id uint) (*models.WorkJob, error) {
+	job, err := s.Repo.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := auth.CheckYard(ctx, job.YardID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job.Status = models.JobStatusInProgress
+	job.StartedAt = &now
+	if err := s.transition(ctx, job, "started", models.JobStatusPending); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// CompleteJob mengkonfirmasi job selesai. Job diklaim sebagai DONE lebih dulu sehingga konfirmasi ganda tidak
+// menjalankan perpindahan dua kali. Perpindahan divalidasi ulang karena kondisi lapangan bisa berubah sejak job
+// dibuat; jika tidak lagi valid job ditandai FAILED dan posisi kontainer tidak diubah.
+func (s *WorkQueueService) CompleteJob(ctx context.Context, id uint) (*models.WorkJob, error) {
+	job, err := s.Repo.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := auth.CheckYard(ctx, job.YardID); err != nil {
+		return nil, err
+	}
+
+	ctx = jobLogContext(ctx, job)
+	now := time.Now()
+	job.Status = models.JobStatusDone
+	job.CompletedAt = &now
+	if err := s.transition(ctx, job, "completed", models.JobStatusPending, models.JobStatusInProgress); err != nil {
+		return nil, err
+	}
+
+	if err := s.commitJob(ctx, job); err != nil {
+		slog.WarnContext(ctx, "job failed", "job_id", job.ID, "job_type", job.JobType, "error", err)
+		if failErr := s.markFailed(ctx, job, err.Error()); failErr != nil {
+			return nil, failErr
+		}
+		return job, fmt.Errorf("job %d failed: %v", id, err)
+	}
+	slog.InfoContext(ctx, "job completed", "job_id", job.ID, "job_type", job.JobType)
+	return job, nil
+}
+
+// FailJob menandai job gagal dikerjakan, contoh: posisi tujuan terhalang
+func (s *WorkQueueService) FailJob(ctx context.Context, id uint, reason string) (*models.WorkJob, error) {
+	job, err := s.Repo.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := auth.CheckYard(ctx, job.YardID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job.Status = models.JobStatusFailed
+	job.FailureReason = reason
+	job.CompletedAt = &now
+	if err := s.transition(ctx, job, "failed", models.JobStatusPending, models.JobStatusInProgress); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// transition menyimpan status baru job jika status job di database masih salah satu dari fromStatuses
+func (s *WorkQueueService) transition(ctx context.Context, job *models.WorkJob, action string, fromStatuses ...string) error {
+	ok, err := s.Repo.TransitionJob(ctx, job, fromStatuses...)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		current, err := s.Repo.GetJob(ctx, job.ID)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("job %d cannot be %s from status %s", job.ID, action, current.Status)
+	}
+	return nil
+}
+
+func (s *WorkQueueService) commitJob(ctx context.Context, job *models.WorkJob) error {
+	switch job.JobType {
+	case models.JobTypePlace:
+		container, err := s.ContainerService.ValidatePlacement(ctx, job.YardID, job.ContainerNumber, job.ToBlockID, job.ToSlot, job.ToRow, job.ToTier, job.Size, job.Height, job.Type, job.LineOperator, job.CargoStatus)
+		if err != nil {
+			return err
+		}
+		return s.ContainerService.CommitPlacement(ctx, container)
+	case models.JobTypePickup:
+		container, err := s.ContainerService.ValidatePickup(ctx, job.YardID, job.ContainerNumber)
+		if err != nil {
+			return err
+		}
+		return s.ContainerService.CommitPickup(ctx, container)
+	case models.JobTypeShift:
+		container, err := s.ContainerService.ValidateShift(ctx, job.YardID, job.ContainerNumber, job.ToBlockID, job.ToSlot, job.ToRow, job.ToTier)
+		if err != nil {
+			return err
+		}
+		return s.ContainerService.CommitShift(ctx, container)
+	}
+	return fmt.Errorf("unsupported job type: %s", job.JobType)
+}
+
+// markFailed mengganti status job yang sudah diklaim CompleteJob menjadi FAILED
+func (s *WorkQueueService) markFailed(ctx context.Context, job *models.WorkJob, reason string) error {
+	now := time.Now()
+	job.Status = models.JobStatusFailed
+	job.FailureReason = reason
+	job.CompletedAt = &now
+	return s.Repo.UpdateJob(ctx, job)
+}
+
+// CheckGateOut menolak kontainer yang keluar di luar antrian (contoh: gate-out CODECO) selama kontainer masih punya
+// job terbuka atau ada job yang menuju posisi di atas kontainer tersebut
+func (s *WorkQueueService) CheckGateOut(ctx context.Context, container *models.Container) error {
+	if err := s.checkNoOpenJob(ctx, container.ContainerNumber); err != nil {
+		return err
+	}
+	reserved, err := s.ReservedPositions(ctx, container.YardID)
+	if err != nil {
+		return err
+	}
+	for _, target := range reserved {
+		if target.BlockID == container.BlockID && target.Row == container.Row && target.Tier >= container.Tier &&
+			target.Slot < container.Slot+container.Size/20 && container.Slot < target.Slot+target.Size/20 {
+			return fmt.Errorf("container %s cannot leave the yard, open job for container %s targets position %d-%d-%d above it",
+				container.ContainerNumber, target.ContainerNumber, target.Slot, target.Row, target.Tier)
+		}
+	}
+	return nil
+}
+
+func (s *WorkQueueService) checkNoOpenJob(ctx context.Context, containerNumber string) error {
+	open, err := s.Repo.GetOpenJobForContainer(ctx, containerNumber)
+	if err != nil {
+		return err
+	}
+	if open != nil {
+		return fmt.Errorf("container %s already has open job %d (%s)", containerNumber, open.ID, open.JobType)
+	}
+	return nil
+}
+
+// enqueue memberikan job ke alat di block dengan antrian paling sedikit lalu menyimpannya. Block tanpa alat
+// menghasilkan job tanpa EquipmentID yang bisa diambil operator mana pun di yard.
+func (s *WorkQueueService) enqueue(ctx context.Context, job *models.WorkJob, blockID string) error {
+	equipment, err := s.EquipmentRepo.GetEquipmentForBlock(ctx, job.YardID, blockID)
+	if err != nil {
+		return err
+	}
+	if len(equipment) > 0 {
+		ids := make([]string, len(equipment))
+		for i, e := range equipment {
+			ids[i] = e.ID
+		}
+		counts, err := s.Repo.CountOpenJobsByEquipment(ctx, ids)
+		if err != nil {
+			return err
+		}
+		job.EquipmentID = ids[0]
+		for _, id := range ids[1:] {
+			if counts[id] < counts[job.EquipmentID] {
+				job.EquipmentID = id
+			}
+		}
+	}
+
+	job.Status = models.JobStatusPending
+	return s.Repo.CreateJob(ctx, job)
+}
+
+// jobLogContext menambah nomor kontainer, yard dan block alat ke context log
+func jobLogContext(ctx context.Context, job *models.WorkJob) context.Context {
+	block := job.ToBlockID
+	if job.FromBlockID != "" {
+		block = job.FromBlockID
+	}
+	return logging.With(ctx, logging.KeyContainer, job.ContainerNumber, logging.KeyYard, job.YardID, logging.KeyBlock, block)
+}
+
+func jobForContainer(jobType string, container *models.Container) *models.WorkJob {
+	return &models.WorkJob{
+		JobType:         jobType,
+		ContainerNumber: container.ContainerNumber,
+		Size:            container.Size,
+		Height:          container.Height,
+		Type:            container.Type,
+		LineOperator:    container.LineOperator,
+		CargoStatus:     container.CargoStatus,
+		YardID:          container.YardID,
+	}
+}
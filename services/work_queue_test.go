@@ -0,0 +1,185 @@
+//go:build cgo
+
+package services
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"yard-calculation/config"
+	"yard-calculation/edifact"
+	"yard-calculation/migrations"
+	"yard-calculation/models"
+)
+
+// newTestContainerServices membangun service seperti server di atas SQLite, karena antrian kerja dan pre-advice
+// hanya punya repository GORM. Layout yard sama dengan newTestContainerService. Butuh cgo untuk driver SQLite.
+func newTestContainerServices(t *testing.T) *ContainerServices {
+	t.Helper()
+	cfg := &config.Config{
+		Database: config.DatabaseConfig{Driver: config.DriverSQLite, Path: filepath.Join(t.TempDir(), "yard.db"), MaxOpenConns: 1},
+		Features: config.FeatureConfig{WorkQueue: true, PreAdviceReservation: true, HoldCheck: true},
+	}
+	if err := config.ConnectDatabase(cfg); err != nil {
+		t.Fatalf("connect sqlite: %v", err)
+	}
+	db := config.DB
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	if _, err := migrations.Up(db); err != nil {
+		t.Fatalf("migrate sqlite: %v", err)
+	}
+
+	core := NewContainerServices(db, cfg, nil)
+	err := core.Container.Repo.ImportLayout(context.Background(),
+		[]models.Yard{{ID: "YRD1", Name: "Yard 1"}},
+		[]models.Block{{ID: "A", Name: "Block A", YardID: "YRD1", TotalSlot: 5, TotalRow: 2, TotalTier: 3}},
+		[]models.YardPlan{
+			{YardID: "YRD1", BlockID: "A", PlannedSize: 20, PlannedHeight: 8.6, PlannedType: "DRY", MinSlot: 1, MaxSlot: 2, MinRow: 1, MaxRow: 2, MinTier: 1, MaxTier: 3},
+			{YardID: "YRD1", BlockID: "A", PlannedSize: 40, PlannedHeight: 9.6, PlannedType: "DRY", MinSlot: 3, MaxSlot: 5, MinRow: 1, MaxRow: 2, MinTier: 1, MaxTier: 3},
+		})
+	if err != nil {
+		t.Fatalf("import layout: %v", err)
+	}
+	return core
+}
+
+func TestCodecoGateOutWithOpenJobs(t *testing.T) {
+	ctx := context.Background()
+	core := newTestContainerServices(t)
+	place := func(number string, slot, row, tier, size int, height float64) {
+		t.Helper()
+		if err := core.Container.PlaceContainerDetailed(ctx, "YRD1", number, "A", slot, row, tier, size, height, "DRY", "MSK", models.CargoStatusFull); err != nil {
+			t.Fatalf("place %s: %v", number, err)
+		}
+	}
+	place("MSKU0000001", 1, 1, 1, 20, 8.6)
+	place("MSKU0000002", 2, 1, 1, 20, 8.6)
+	place("MSKU0000003", 1, 2, 1, 20, 8.6)
+	place("MSKU0000004", 3, 1, 1, 40, 9.6)
+
+	if _, err := core.WorkQueue.CreatePickupJob(ctx, "YRD1", "MSKU0000001"); err != nil {
+		t.Fatalf("create pickup job: %v", err)
+	}
+	if _, err := core.WorkQueue.CreatePlaceJob(ctx, "YRD1", "MSKU0000005", "A", 2, 1, 2, 20, 8.6, "DRY", "MSK", models.CargoStatusFull); err != nil {
+		t.Fatalf("create place job: %v", err)
+	}
+	if _, err := core.WorkQueue.CreatePlaceJob(ctx, "YRD1", "MSKU0000006", "A", 3, 1, 2, 40, 9.6, "DRY", "MSK", models.CargoStatusFull); err != nil {
+		t.Fatalf("create place job: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		number  string
+		wantErr string
+	}{
+		{"own open job", "MSKU0000001", "container MSKU0000001 already has open job 1 (PICKUP)"},
+		{"job targets position above", "MSKU0000002", "container MSKU0000002 cannot leave the yard, open job for container MSKU0000005 targets position 2-1-2 above it"},
+		{"40ft job targets position above", "MSKU0000004", "container MSKU0000004 cannot leave the yard, open job for container MSKU0000006 targets position 3-1-2 above it"},
+		{"no open job", "MSKU0000003", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := core.Codeco.applyGateOut(ctx, "YRD1", edifact.CodecoEvent{ContainerNumber: tt.number})
+			if gotErr := errText(err); gotErr != tt.wantErr {
+				t.Fatalf("gate-out error = %q, want %q", gotErr, tt.wantErr)
+			}
+			container, err := core.Container.Repo.FindContainerByNumber(ctx, tt.number)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if container.IsPlaced != (tt.wantErr != "") {
+				t.Errorf("IsPlaced = %v after gate-out", container.IsPlaced)
+			}
+		})
+	}
+}
+
+func TestWorkQueueJobTransitions(t *testing.T) {
+	ctx := context.Background()
+	core := newTestContainerServices(t)
+	recorder := &eventRecorder{}
+	core.Container.AddListener(recorder)
+
+	job, err := core.WorkQueue.CreatePlaceJob(ctx, "YRD1", "MSKU0000001", "A", 1, 1, 1, 20, 8.6, "DRY", "MSK", models.CargoStatusFull)
+	if err != nil {
+		t.Fatalf("create place job: %v", err)
+	}
+	if _, err := core.WorkQueue.StartJob(ctx, job.ID); err != nil {
+		t.Fatalf("start job: %v", err)
+	}
+	if _, err := core.WorkQueue.StartJob(ctx, job.ID); errText(err) != "job 1 cannot be started from status IN_PROGRESS" {
+		t.Fatalf("second start error = %v", err)
+	}
+
+	// Konfirmasi ganda dari beberapa operator sekaligus hanya boleh menyimpan penempatan sekali
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var completed, rejected int
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := core.WorkQueue.CompleteJob(ctx, job.ID)
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil {
+				completed++
+			} else if errText(err) == "job 1 cannot be completed from status DONE" {
+				rejected++
+			} else {
+				t.Errorf("complete job: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+	if completed != 1 || rejected != 4 {
+		t.Fatalf("completed %d, rejected %d; want 1 and 4", completed, rejected)
+	}
+	if len(recorder.events) != 1 || recorder.events[0] != "PLACED MSKU0000001" {
+		t.Fatalf("events = %v, want one placement", recorder.events)
+	}
+	if _, err := core.WorkQueue.FailJob(ctx, job.ID, "blocked"); errText(err) != "job 1 cannot be failed from status DONE" {
+		t.Fatalf("fail after complete error = %v", err)
+	}
+}
+
+func TestCodecoGateInCargoStatus(t *testing.T) {
+	ctx := context.Background()
+	core := newTestContainerServices(t)
+	for _, number := range []string{"MSKU0000001", "MSKU0000003"} {
+		if err := core.Codeco.applyGateIn(ctx, "YRD1", edifact.CodecoEvent{ContainerNumber: number, SizeType: "22G1", GateIn: true}); err != nil {
+			t.Fatalf("gate-in %s: %v", number, err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		number      string
+		slot, row   int
+		cargoStatus string
+		want        string
+	}{
+		{"empty from gate-in", "MSKU0000001", 1, 1, "", models.CargoStatusEmpty},
+		{"no gate-in defaults to full", "MSKU0000002", 2, 1, "", models.CargoStatusFull},
+		{"request overrides gate-in", "MSKU0000003", 1, 2, models.CargoStatusFull, models.CargoStatusFull},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := core.Container.PlaceContainerDetailed(ctx, "YRD1", tt.number, "A", tt.slot, tt.row, 1, 20, 8.6, "DRY", "MSK", tt.cargoStatus); err != nil {
+				t.Fatalf("place: %v", err)
+			}
+			container, err := core.Container.Repo.FindContainerByNumber(ctx, tt.number)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if container.CargoStatus != tt.want {
+				t.Errorf("cargo status = %q, want %q", container.CargoStatus, tt.want)
+			}
+		})
+	}
+}
@@ -1,20 +1,24 @@
 package utils
 
 import (
+	"yard-calculation/logging"
+
 	"github.com/gofiber/fiber/v2"
 )
 
 type ResponseFormat struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Data    any    `json:"data,omitempty"`
-	Error   any    `json:"error,omitempty"`
+	Code      int    `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"` // Sama dengan header X-Request-ID dan field request_id di log
+	Data      any    `json:"data,omitempty"`
+	Error     any    `json:"error,omitempty"`
 }
 
 func ApiResponse(c *fiber.Ctx, code int, message string, data any, error any) {
 	jsonResponse := ResponseFormat{
-		Code:    code,
-		Message: message,
+		Code:      code,
+		Message:   message,
+		RequestID: logging.RequestID(c.UserContext()),
 	}
 
 	if data != nil {
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Table adalah isi satu file CSV atau satu sheet XLSX, baris pertama dianggap header
+type Table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// Get mengambil nilai kolom berdasarkan nama header, kosong jika kolom tidak ada
+func (t *Table) Get(row []string, column string) string {
+	for i, h := range t.Header {
+		if strings.EqualFold(strings.TrimSpace(h), column) {
+			if i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+func IsXLSX(filename string) bool {
+	return strings.EqualFold(filepath.Ext(filename), ".xlsx")
+}
+
+func ReadCSVTable(r io.Reader) (*Table, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error reading csv: %v", err)
+	}
+	return newTable(records), nil
+}
+
+// ReadXLSXTables membaca semua sheet, key map adalah nama sheet dalam huruf kecil
+func ReadXLSXTables(r io.Reader) (map[string]*Table, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading xlsx: %v", err)
+	}
+	defer f.Close()
+
+	tables := make(map[string]*Table)
+	for _, sheet := range f.GetSheetList() {
+		rows, err := f.GetRows(sheet)
+		if err != nil {
+			return nil, fmt.Errorf("error reading sheet %s: %v", sheet, err)
+		}
+		tables[strings.ToLower(strings.TrimSpace(sheet))] = newTable(rows)
+	}
+	return tables, nil
+}
+
+func newTable(records [][]string) *Table {
+	if len(records) == 0 {
+		return &Table{}
+	}
+	return &Table{Header: records[0], Rows: records[1:]}
+}